@@ -0,0 +1,30 @@
+package byteset
+
+import (
+	"context"
+	stdsql "database/sql"
+	"fmt"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// connectDatabase loads the database from the given address,
+// and waits until the database is connected.
+func connectDatabase(ctx context.Context, addr string) (string, *stdsql.DB, error) {
+	ctx = sqlx.MaskSecrets(ctx, addr)
+
+	// Load database.
+	drv, db, err := sqlx.LoadDatabase(addr, 1)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot load database from %q: %w", sqlx.RedactAddress(addr), sqlx.RedactError(err, addr))
+	}
+
+	// Detect connectivity.
+	err = sqlx.IsDatabaseConnected(ctx, db, sqlx.Wait{})
+	if err != nil {
+		_ = db.Close()
+		return "", nil, fmt.Errorf("cannot connect database on %q: %w", sqlx.RedactAddress(addr), sqlx.RedactError(err, addr))
+	}
+
+	return drv, db, nil
+}
@@ -0,0 +1,161 @@
+package byteset
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+var _ datasource.DataSource = (*DataSourceDatabaseInfo)(nil)
+
+type DataSourceDatabaseInfoTable struct {
+	Name types.String `tfsdk:"name"`
+	Rows types.Int64  `tfsdk:"rows"`
+}
+
+type DataSourceDatabaseInfo struct {
+	Address    types.String                  `tfsdk:"address"`
+	Driver     types.String                  `tfsdk:"driver"`
+	Version    types.String                  `tfsdk:"version"`
+	Database   types.String                  `tfsdk:"database"`
+	SchemaName types.String                  `tfsdk:"schema"`
+	Charset    types.String                  `tfsdk:"charset"`
+	Collation  types.String                  `tfsdk:"collation"`
+	Tables     []DataSourceDatabaseInfoTable `tfsdk:"tables"`
+	Extensions map[string]string             `tfsdk:"extensions"`
+}
+
+func NewDataSourceDatabaseInfo() datasource.DataSource {
+	return DataSourceDatabaseInfo{}
+}
+
+func (r DataSourceDatabaseInfo) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = strings.Join([]string{req.ProviderTypeName, "database_info"}, "_")
+}
+
+func (r DataSourceDatabaseInfo) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		Description: `Specify the database to introspect the server information.`,
+		Attributes: map[string]schema.Attribute{
+			"address": schema.StringAttribute{
+				Required:  true,
+				Sensitive: true,
+				Description: `The address of database, which to introspect.
+
+  - Database address format:
+	  - mysql://[username:[password]@][protocol([address][:port])][/dbname][?param1=value1&...]
+	  - maria|mariadb://[username:[password]@][protocol([address][:port])][/dbname][?param1=value1&...]
+	  - postgres|postgresql://[username:[password]@][address][:port][/dbname][?param1=value1&...]
+	  - oracle://[username:[password]@][address][:port][/service][?param1=value1&...]
+	  - mssql|sqlserver://[username:[password]@][address][:port][/instance][?database=dbname&param1=value1&...]`,
+			},
+			"driver": schema.StringAttribute{
+				Computed:    true,
+				Description: `The driver of database, one of mysql, postgres, oracle and mssql.`,
+			},
+			"version": schema.StringAttribute{
+				Computed:    true,
+				Description: `The version of database server.`,
+			},
+			"database": schema.StringAttribute{
+				Computed:    true,
+				Description: `The current database.`,
+			},
+			"schema": schema.StringAttribute{
+				Computed:    true,
+				Description: `The current schema.`,
+			},
+			"charset": schema.StringAttribute{
+				Computed:    true,
+				Description: `The character set of the current database.`,
+			},
+			"collation": schema.StringAttribute{
+				Computed:    true,
+				Description: `The collation of the current database.`,
+			},
+			"tables": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: `The tables of the current schema.`,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: `The name of table.`,
+						},
+						"rows": schema.Int64Attribute{
+							Computed:    true,
+							Description: `The approximate row count of table.`,
+						},
+					},
+				},
+			},
+			"extensions": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: `The installed extensions(plugins for MySQL) and their versions,
+empty for Oracle and SQLServer.`,
+			},
+		},
+	}
+}
+
+func (r DataSourceDatabaseInfo) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	state := r
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	drv, db, err := connectDatabase(ctx, state.Address.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Database",
+			"Cannot connect database: "+err.Error())
+
+		return
+	}
+
+	defer func() { _ = db.Close() }()
+
+	info, err := sqlx.Introspect(ctx, drv, db)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed Introspect",
+			"Cannot introspect database: "+err.Error())
+
+		return
+	}
+
+	state.Driver = types.StringValue(drv)
+	state.Version = types.StringValue(info.Version)
+	state.Database = types.StringValue(info.Database)
+	state.SchemaName = types.StringValue(info.Schema)
+	state.Charset = types.StringValue(info.Charset)
+	state.Collation = types.StringValue(info.Collation)
+	state.Extensions = info.Extensions
+
+	state.Tables = make([]DataSourceDatabaseInfoTable, len(info.Tables))
+	for i := range info.Tables {
+		state.Tables[i] = DataSourceDatabaseInfoTable{
+			Name: types.StringValue(info.Tables[i].Name),
+			Rows: types.Int64Value(info.Tables[i].Rows),
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
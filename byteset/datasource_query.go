@@ -0,0 +1,244 @@
+package byteset
+
+import (
+	"context"
+	stdsql "database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+var _ datasource.DataSource = (*DataSourceQuery)(nil)
+
+type DataSourceQuery struct {
+	Address  types.String `tfsdk:"address"`
+	Query    types.String `tfsdk:"query"`
+	Args     types.List   `tfsdk:"args"`
+	Columns  types.List   `tfsdk:"columns"`
+	Rows     types.List   `tfsdk:"rows"`
+	RowsJSON types.String `tfsdk:"rows_json"`
+}
+
+func NewDataSourceQuery() datasource.DataSource {
+	return DataSourceQuery{}
+}
+
+func (r DataSourceQuery) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = strings.Join([]string{req.ProviderTypeName, "query"}, "_")
+}
+
+func (r DataSourceQuery) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Specify the read-only query to read rows back from database.`,
+		Attributes: map[string]schema.Attribute{
+			"address": schema.StringAttribute{
+				Required:  true,
+				Sensitive: true,
+				Description: `The address of database, which to query from.
+
+  - Database address format:
+	  - mysql://[username:[password]@][protocol([address][:port])][/dbname][?param1=value1&...]
+	  - maria|mariadb://[username:[password]@][protocol([address][:port])][/dbname][?param1=value1&...]
+	  - postgres|postgresql://[username:[password]@][address][:port][/dbname][?param1=value1&...]
+	  - oracle://[username:[password]@][address][:port][/service][?param1=value1&...]
+	  - mssql|sqlserver://[username:[password]@][address][:port][/instance][?database=dbname&param1=value1&...]`,
+			},
+			"query": schema.StringAttribute{
+				Required: true,
+				Description: `The read-only SQL to execute,
+like SELECT/WITH/VALUES/TABLE/SHOW/DESCRIBE/EXPLAIN statement,
+which is executed in a read-only transaction on MySQL, Postgres and Oracle,
+and must be exactly one SELECT statement on SQLServer.`,
+			},
+			"args": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: `The arguments to bind the placeholders of the query.`,
+			},
+			"columns": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: `The column names of the result.`,
+			},
+			"rows": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.MapType{ElemType: types.StringType},
+				Description: `The rows of the result, each value is converted to string,
+or null if the value is NULL.`,
+			},
+			"rows_json": schema.StringAttribute{
+				Computed: true,
+				Description: `The rows of the result in JSON format, each value keeps the type,
+decode it via jsondecode function.`,
+			},
+		},
+	}
+}
+
+func (r DataSourceQuery) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	state := r
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := state.Query.ValueString()
+	if !sqlx.IsReadOnly(query) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("query"),
+			"Invalid Query",
+			"Query must be a read-only statement")
+
+		return
+	}
+
+	var args []string
+
+	resp.Diagnostics.Append(state.Args.ElementsAs(ctx, &args, true)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cols, rows, err := queryDatabase(ctx, state.Address.ValueString(), query, args)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed Query",
+			"Cannot query database: "+err.Error())
+
+		return
+	}
+
+	// Convert to Terraform values.
+	rowVals := make([]map[string]*string, len(rows))
+	rowJSONs := make([]map[string]any, len(rows))
+
+	for i := range rows {
+		rowVals[i] = make(map[string]*string, len(cols))
+		rowJSONs[i] = make(map[string]any, len(cols))
+
+		for j := range cols {
+			rowJSONs[i][cols[j]] = rows[i][j]
+
+			if rows[i][j] == nil {
+				rowVals[i][cols[j]] = nil
+				continue
+			}
+
+			v := stringify(rows[i][j])
+			rowVals[i][cols[j]] = &v
+		}
+	}
+
+	var diags diag.Diagnostics
+
+	state.Columns, diags = types.ListValueFrom(ctx, types.StringType, cols)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Rows, diags = types.ListValueFrom(ctx, types.MapType{ElemType: types.StringType}, rowVals)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bs, err := json.Marshal(rowJSONs)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed Query",
+			"Cannot encode rows: "+err.Error())
+
+		return
+	}
+	state.RowsJSON = types.StringValue(string(bs))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// queryDatabase executes the given read-only query in a transaction which is always rolled back,
+// the transaction is read-only on MySQL, Postgres and Oracle,
+// and the query must be exactly one SELECT statement on SQLServer, which has no read-only transaction.
+func queryDatabase(ctx context.Context, addr, query string, args []string) ([]string, [][]any, error) {
+	drv, _, err := sqlx.ParseAddress(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if drv == sqlx.SQLServerDialect && !sqlx.IsSingleSelect(query) {
+		return nil, nil, errors.New("query must be exactly one SELECT statement on mssql")
+	}
+
+	drv, db, err := connectDatabase(ctx, addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	qargs := make([]any, len(args))
+	for i := range args {
+		qargs[i] = args[i]
+	}
+
+	// Protect with read-only transaction.
+	var opts *stdsql.TxOptions
+
+	switch drv {
+	case sqlx.MySQLDialect, sqlx.PostgresDialect:
+		opts = &stdsql.TxOptions{ReadOnly: true}
+	}
+
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	if drv == sqlx.OracleDialect {
+		// Must be the first statement of the transaction.
+		err = sqlx.Exec(ctx, tx, "SET TRANSACTION READ ONLY")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return sqlx.Query(ctx, tx, query, qargs...)
+}
+
+// stringify returns the string of the given typed value.
+func stringify(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case map[string]any, []any:
+		bs, _ := json.Marshal(t)
+		return string(bs)
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,163 @@
+package byteset
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/seal-io/terraform-provider-byteset/pipeline"
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+var _ datasource.DataSource = (*DataSourceSQLFile)(nil)
+
+type DataSourceSQLFile struct {
+	Address      types.String     `tfsdk:"address"`
+	Dialect      types.String     `tfsdk:"dialect"`
+	Digest       types.String     `tfsdk:"digest"`
+	Statements   map[string]int64 `tfsdk:"statements"`
+	Tables       []string         `tfsdk:"tables"`
+	InsertRows   map[string]int64 `tfsdk:"insert_rows"`
+	Unclassified []string         `tfsdk:"unclassified"`
+}
+
+func NewDataSourceSQLFile() datasource.DataSource {
+	return DataSourceSQLFile{}
+}
+
+func (r DataSourceSQLFile) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = strings.Join([]string{req.ProviderTypeName, "sql_file"}, "_")
+}
+
+func (r DataSourceSQLFile) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Specify the SQL file to parse and summarize without executing.`,
+		Attributes: map[string]schema.Attribute{
+			"address": schema.StringAttribute{
+				Required: true,
+				Description: `The address of SQL file, which to parse.
+
+  - Local/Remote SQL file format:
+	  - file:///path/to/filename
+	  - http(s)://...
+	  - raw://...
+	  - raw+base64://...`,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^(file|https?|raw|raw\+base64)://`),
+						"must be a local/remote SQL file address"),
+				},
+			},
+			"dialect": schema.StringAttribute{
+				Optional: true,
+				Description: `The dialect to parse the SQL file with, default is mysql,
+choose from mysql, postgres, oracle and mssql.`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						sqlx.MySQLDialect,
+						sqlx.PostgresDialect,
+						sqlx.OracleDialect,
+						sqlx.SQLServerDialect),
+				},
+			},
+			"digest": schema.StringAttribute{
+				Computed:    true,
+				Description: `The digest of the SQL file.`,
+			},
+			"statements": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.Int64Type,
+				Description: `The statement count by type, the keys are ddl, dml, tcl, dcl and unknown.`,
+			},
+			"tables": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: `The tables created or written by the statements, in order of their first appearance.`,
+			},
+			"insert_rows": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.Int64Type,
+				Description: `The inserting row count by table.`,
+			},
+			"unclassified": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: `The statements cannot be classified, which are ignored when piping.`,
+			},
+		},
+	}
+}
+
+func (r DataSourceSQLFile) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	state := r
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	drv := sqlx.MySQLDialect
+	if v := state.Dialect.ValueString(); v != "" {
+		drv = v
+	}
+
+	src, err := pipeline.NewSource(ctx, state.Address.ValueString(), 1, sqlx.Wait{})
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("address"),
+			"Invalid Source",
+			"Cannot reflect from source: "+err.Error())
+
+		return
+	}
+
+	defer func() { _ = src.Close() }()
+
+	dg, err := src.Digest()
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("address"),
+			"Invalid Source",
+			"Cannot digest source: "+err.Error())
+
+		return
+	}
+
+	sum := pipeline.NewSummary(drv)
+
+	if err = src.Pipe(ctx, sum); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed Parse",
+			"Cannot parse source: "+err.Error())
+
+		return
+	}
+
+	state.Digest = types.StringValue(dg)
+	state.Tables = append([]string{}, sum.Tables...)
+	state.Unclassified = append([]string{}, sum.Unclassified...)
+
+	state.Statements = make(map[string]int64, len(sum.Statements))
+	for k, v := range sum.Statements {
+		state.Statements[k] = int64(v)
+	}
+
+	state.InsertRows = make(map[string]int64, len(sum.InsertRows))
+	for k, v := range sum.InsertRows {
+		state.InsertRows[k] = int64(v)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -2,11 +2,20 @@ package byteset
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
 	"github.com/seal-io/terraform-provider-byteset/utils/version"
 )
 
@@ -19,6 +28,104 @@ const (
 	ProviderAddress   = ProviderHostname + "/" + ProviderNamespace + "/" + ProviderType
 )
 
+const (
+	defaultConnMax  = 5
+	defaultBatchCap = 500
+	defaultTimeout  = 30 * time.Minute
+)
+
+type ProviderConnection struct {
+	Address  types.String         `tfsdk:"address"`
+	ConnMax  types.Int64          `tfsdk:"conn_max"`
+	Username types.String         `tfsdk:"username"`
+	Password types.String         `tfsdk:"password"`
+	TLS      *ResourcePipelineTLS `tfsdk:"tls"`
+}
+
+type ProviderDefaults struct {
+	ConnMax   types.Int64  `tfsdk:"conn_max"`
+	BatchCap  types.Int64  `tfsdk:"batch_cap"`
+	Timeout   types.String `tfsdk:"timeout"`
+	LogSQL    types.Bool   `tfsdk:"log_sql"`
+	RedactSQL types.Bool   `tfsdk:"redact_sql"`
+}
+
+type ProviderConfig struct {
+	Connections map[string]ProviderConnection `tfsdk:"connections"`
+	Defaults    *ProviderDefaults             `tfsdk:"defaults"`
+}
+
+// Address returns the database address of the given named connection.
+func (c *ProviderConfig) Address(name string) (string, error) {
+	if c == nil {
+		return "", fmt.Errorf("connection %q is not declared in provider", name)
+	}
+
+	conn, ok := c.Connections[name]
+	if !ok {
+		return "", fmt.Errorf("connection %q is not declared in provider", name)
+	}
+
+	addr, err := sqlx.WithCredentials(
+		conn.Address.ValueString(),
+		conn.Username.ValueString(),
+		conn.Password.ValueString())
+	if err != nil {
+		return "", err
+	}
+
+	return conn.TLS.Apply(addr)
+}
+
+// ConnMax returns the maximum connections of the given named connection,
+// falls back to the provider defaults if the name is blank or not found.
+func (c *ProviderConfig) ConnMax(name string) int64 {
+	if c != nil {
+		if conn, ok := c.Connections[name]; ok && !conn.ConnMax.IsNull() {
+			return conn.ConnMax.ValueInt64()
+		}
+
+		if c.Defaults != nil && !c.Defaults.ConnMax.IsNull() {
+			return c.Defaults.ConnMax.ValueInt64()
+		}
+	}
+
+	return defaultConnMax
+}
+
+// BatchCap returns the default batch capacity.
+func (c *ProviderConfig) BatchCap() int64 {
+	if c != nil && c.Defaults != nil && !c.Defaults.BatchCap.IsNull() {
+		return c.Defaults.BatchCap.ValueInt64()
+	}
+
+	return defaultBatchCap
+}
+
+// Timeout returns the default timeout of operations.
+func (c *ProviderConfig) Timeout() time.Duration {
+	if c != nil && c.Defaults != nil && !c.Defaults.Timeout.IsNull() {
+		if d, err := time.ParseDuration(c.Defaults.Timeout.ValueString()); err == nil {
+			return d
+		}
+	}
+
+	return defaultTimeout
+}
+
+// Context returns the context with the logging defaults.
+func (c *ProviderConfig) Context(ctx context.Context) context.Context {
+	if c != nil && c.Defaults != nil && !c.Defaults.LogSQL.IsNull() && !c.Defaults.LogSQL.ValueBool() {
+		ctx = tflog.OmitLogWithFieldKeys(ctx, "sql")
+	}
+
+	if c != nil && c.Defaults != nil && c.Defaults.RedactSQL.ValueBool() {
+		ctx = sqlx.WithRedactLiterals(ctx)
+	}
+
+	return ctx
+}
+
 type Provider struct{}
 
 func NewProvider() provider.Provider {
@@ -31,13 +138,142 @@ func (p Provider) Metadata(ctx context.Context, req provider.MetadataRequest, re
 }
 
 func (p Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"connections": schema.MapNestedAttribute{
+				Optional: true,
+				Description: `The named connections of database,
+which can be referred by the connection attribute of pipeline source or destination.`,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"address": schema.StringAttribute{
+							Required:  true,
+							Sensitive: true,
+							Description: `The address of database.
+
+  - Database address format:
+	  - mysql://[username:[password]@][protocol([address][:port])][/dbname][?param1=value1&...]
+	  - maria|mariadb://[username:[password]@][protocol([address][:port])][/dbname][?param1=value1&...]
+	  - postgres|postgresql://[username:[password]@][address][:port][/dbname][?param1=value1&...]
+	  - oracle://[username:[password]@][address][:port][/service][?param1=value1&...]
+	  - mssql|sqlserver://[username:[password]@][address][:port][/instance][?database=dbname&param1=value1&...]`,
+						},
+						"conn_max": schema.Int64Attribute{
+							Optional:    true,
+							Description: `The maximum opening connectors of database.`,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+						"username": schema.StringAttribute{
+							Optional:    true,
+							Description: `The username to replace the one of address.`,
+						},
+						"password": schema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: `The password to replace the one of address.`,
+						},
+						"tls": schema.SingleNestedAttribute{
+							Optional:    true,
+							Description: `The TLS configuration of database.`,
+							Attributes: map[string]schema.Attribute{
+								"mode": schema.StringAttribute{
+									Required:    true,
+									Description: tlsModeDescription,
+									Validators:  tlsModeValidators(),
+								},
+								"ca": schema.StringAttribute{
+									Optional:    true,
+									Description: tlsCADescription,
+								},
+								"cert": schema.StringAttribute{
+									Optional:    true,
+									Description: tlsCertDescription,
+								},
+								"key": schema.StringAttribute{
+									Optional:    true,
+									Sensitive:   true,
+									Description: tlsKeyDescription,
+								},
+								"server_name": schema.StringAttribute{
+									Optional:    true,
+									Description: tlsServerNameDescription,
+								},
+							},
+						},
+					},
+				},
+			},
+			"defaults": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: `The defaults of all pipelines.`,
+				Attributes: map[string]schema.Attribute{
+					"conn_max": schema.Int64Attribute{
+						Optional:    true,
+						Description: `The default maximum opening connectors of database, default is 5.`,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"batch_cap": schema.Int64Attribute{
+						Optional: true,
+						Description: `The default maximum value statement number for once insert statements,
+default is 500.`,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"timeout": schema.StringAttribute{
+						Optional: true,
+						Description: `The default timeout of creating, updating and deleting, default is 30m,
+in form of Go duration, like 30s, 10m or 1h.`,
+					},
+					"log_sql": schema.BoolAttribute{
+						Optional:    true,
+						Description: `Log the executed SQL at debug level, default is true.`,
+					},
+					"redact_sql": schema.BoolAttribute{
+						Optional: true,
+						Description: `Replace the string and numeric literals of the logged SQL with ?,
+and omit the bound arguments, default is false.`,
+					},
+				},
+			},
+		},
+	}
 }
 
 func (p Provider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var cfg ProviderConfig
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &cfg)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if cfg.Defaults != nil && !cfg.Defaults.Timeout.IsNull() && !cfg.Defaults.Timeout.IsUnknown() {
+		if _, err := time.ParseDuration(cfg.Defaults.Timeout.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("defaults").AtName("timeout"),
+				"Invalid Timeout",
+				"Cannot parse timeout: "+err.Error())
+
+			return
+		}
+	}
+
+	resp.DataSourceData = &cfg
+	resp.ResourceData = &cfg
 }
 
 func (p Provider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewDataSourceQuery,
+		NewDataSourceDatabaseInfo,
+		NewDataSourceSQLFile,
+	}
 }
 
 func (p Provider) Resources(ctx context.Context) []func() resource.Resource {
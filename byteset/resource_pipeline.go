@@ -2,62 +2,647 @@ package byteset
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/seal-io/terraform-provider-byteset/pipeline"
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
 	"github.com/seal-io/terraform-provider-byteset/utils/strx"
 )
 
-var _ resource.Resource = (*ResourcePipeline)(nil)
+var (
+	_ resource.Resource                = (*ResourcePipeline)(nil)
+	_ resource.ResourceWithConfigure   = (*ResourcePipeline)(nil)
+	_ resource.ResourceWithModifyPlan  = (*ResourcePipeline)(nil)
+	_ resource.ResourceWithImportState = (*ResourcePipeline)(nil)
+)
+
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
 
 type ResourcePipelineSource struct {
-	Address types.String `tfsdk:"address"`
-	ConnMax types.Int64  `tfsdk:"conn_max"`
+	Address      types.String         `tfsdk:"address"`
+	Connection   types.String         `tfsdk:"connection"`
+	Driver       types.String         `tfsdk:"driver"`
+	Host         types.String         `tfsdk:"host"`
+	Port         types.Int64          `tfsdk:"port"`
+	Username     types.String         `tfsdk:"username"`
+	Password     types.String         `tfsdk:"password"`
+	Database     types.String         `tfsdk:"database"`
+	Params       types.Map            `tfsdk:"params"`
+	ConnMax      types.Int64          `tfsdk:"conn_max"`
+	WaitTimeout  types.String         `tfsdk:"wait_timeout"`
+	WaitInterval types.String         `tfsdk:"wait_interval"`
+	WaitQuery    types.String         `tfsdk:"wait_query"`
+	TLS          *ResourcePipelineTLS `tfsdk:"tls"`
+}
+
+// Key returns the key of the structured attributes without the secrets,
+// which is blank if driver is not specified.
+func (r ResourcePipelineSource) Key() string {
+	if r.Driver.ValueString() == "" {
+		return ""
+	}
+
+	return strings.Join([]string{
+		r.Driver.ValueString(),
+		r.Username.ValueString(),
+		r.Host.ValueString(),
+		strconv.FormatInt(r.Port.ValueInt64(), 10),
+		r.Database.ValueString(),
+	}, "/")
+}
+
+// Resolve returns the address of source,
+// which is built from the structured attributes if driver is specified.
+func (r ResourcePipelineSource) Resolve(ctx context.Context, cfg *ProviderConfig) (string, error) {
+	if r.Driver.ValueString() == "" {
+		return resolveAddress(cfg, r.Address, r.Connection)
+	}
+
+	e := sqlx.Endpoint{
+		Driver:   r.Driver.ValueString(),
+		Host:     r.Host.ValueString(),
+		Port:     int(r.Port.ValueInt64()),
+		Username: r.Username.ValueString(),
+		Password: r.Password.ValueString(),
+		Database: r.Database.ValueString(),
+	}
+
+	if !r.Params.IsNull() && !r.Params.IsUnknown() {
+		diags := r.Params.ElementsAs(ctx, &e.Params, false)
+		if diags.HasError() {
+			return "", fmt.Errorf("cannot read params: %v", diags)
+		}
+	}
+
+	return sqlx.BuildAddress(e)
 }
 
-func (r ResourcePipelineSource) Reflect(ctx context.Context) (pipeline.Source, error) {
+func (r ResourcePipelineSource) Reflect(ctx context.Context, cfg *ProviderConfig) (pipeline.Source, error) {
+	addr, err := r.Resolve(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err = r.TLS.Apply(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := waitOf(r.WaitTimeout, r.WaitInterval, r.WaitQuery)
+	if err != nil {
+		return nil, err
+	}
+
 	return pipeline.NewSource(
 		ctx,
-		r.Address.ValueString(),
+		addr,
 		int(r.ConnMax.ValueInt64()),
+		w,
 	)
 }
 
 type ResourcePipelineDestination struct {
-	Address  types.String `tfsdk:"address"`
-	ConnMax  types.Int64  `tfsdk:"conn_max"`
-	BatchCap types.Int64  `tfsdk:"batch_cap"`
-	Salt     types.String `tfsdk:"salt"`
+	Address        types.String         `tfsdk:"address"`
+	Connection     types.String         `tfsdk:"connection"`
+	Driver         types.String         `tfsdk:"driver"`
+	Host           types.String         `tfsdk:"host"`
+	Port           types.Int64          `tfsdk:"port"`
+	Username       types.String         `tfsdk:"username"`
+	Password       types.String         `tfsdk:"password"`
+	Database       types.String         `tfsdk:"database"`
+	Params         types.Map            `tfsdk:"params"`
+	ConnMax        types.Int64          `tfsdk:"conn_max"`
+	BatchCap       types.Int64          `tfsdk:"batch_cap"`
+	Salt           types.String         `tfsdk:"salt"`
+	Resume         types.Bool           `tfsdk:"resume"`
+	RetryAttempts  types.Int64          `tfsdk:"retry_attempts"`
+	RetryBackoff   types.String         `tfsdk:"retry_backoff"`
+	Bulk           types.Bool           `tfsdk:"bulk"`
+	ParallelTables types.Bool           `tfsdk:"parallel_tables"`
+	DependsOn      types.Map            `tfsdk:"depends_on"`
+	FastLoad       types.Bool           `tfsdk:"fast_load"`
+	Atomic         types.Bool           `tfsdk:"atomic"`
+	OnError        types.String         `tfsdk:"on_error"`
+	MaxErrors      types.Int64          `tfsdk:"max_errors"`
+	RejectPath     types.String         `tfsdk:"reject_path"`
+	WaitTimeout    types.String         `tfsdk:"wait_timeout"`
+	WaitInterval   types.String         `tfsdk:"wait_interval"`
+	WaitQuery      types.String         `tfsdk:"wait_query"`
+	TLS            *ResourcePipelineTLS `tfsdk:"tls"`
 }
 
-func (r ResourcePipelineDestination) Reflect(ctx context.Context) (pipeline.Destination, error) {
-	return pipeline.NewDestination(
-		ctx,
-		r.Address.ValueString(),
-		int(r.ConnMax.ValueInt64()),
-		int(r.BatchCap.ValueInt64()),
-	)
+// Key returns the key of the structured attributes without the secrets,
+// which is blank if driver is not specified.
+func (r ResourcePipelineDestination) Key() string {
+	if r.Driver.ValueString() == "" {
+		return ""
+	}
+
+	return strings.Join([]string{
+		r.Driver.ValueString(),
+		r.Username.ValueString(),
+		r.Host.ValueString(),
+		strconv.FormatInt(r.Port.ValueInt64(), 10),
+		r.Database.ValueString(),
+	}, "/")
+}
+
+// Resolve returns the address of destination,
+// which is built from the structured attributes if driver is specified.
+func (r ResourcePipelineDestination) Resolve(ctx context.Context, cfg *ProviderConfig) (string, error) {
+	if r.Driver.ValueString() == "" {
+		return resolveAddress(cfg, r.Address, r.Connection)
+	}
+
+	e := sqlx.Endpoint{
+		Driver:   r.Driver.ValueString(),
+		Host:     r.Host.ValueString(),
+		Port:     int(r.Port.ValueInt64()),
+		Username: r.Username.ValueString(),
+		Password: r.Password.ValueString(),
+		Database: r.Database.ValueString(),
+	}
+
+	if !r.Params.IsNull() && !r.Params.IsUnknown() {
+		diags := r.Params.ElementsAs(ctx, &e.Params, false)
+		if diags.HasError() {
+			return "", fmt.Errorf("cannot read params: %v", diags)
+		}
+	}
+
+	return sqlx.BuildAddress(e)
+}
+
+// Reflect returns the Destination,
+// the given src is used to resume the progress, which can be nil if not resuming.
+func (r ResourcePipelineDestination) Reflect(
+	ctx context.Context,
+	cfg *ProviderConfig,
+	src pipeline.Source,
+) (pipeline.Destination, error) {
+	addr, err := r.Resolve(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err = r.TLS.Apply(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := pipeline.DestinationOptions{
+		ConnMax:  int(r.ConnMax.ValueInt64()),
+		BatchCap: int(r.BatchCap.ValueInt64()),
+		Retry: sqlx.Retry{
+			Attempts:   int(r.RetryAttempts.ValueInt64()),
+			Backoff:    time.Second,
+			BackoffMax: 30 * time.Second,
+		},
+		Bulk:       r.Bulk.ValueBool(),
+		Parallel:   r.ParallelTables.ValueBool(),
+		FastLoad:   r.FastLoad.ValueBool(),
+		Atomic:     r.Atomic.ValueBool(),
+		OnError:    r.OnError.ValueString(),
+		MaxErrors:  int(r.MaxErrors.ValueInt64()),
+		RejectPath: r.RejectPath.ValueString(),
+	}
+
+	if !r.DependsOn.IsNull() && !r.DependsOn.IsUnknown() {
+		diags := r.DependsOn.ElementsAs(ctx, &opts.DependsOn, false)
+		if diags.HasError() {
+			return nil, fmt.Errorf("cannot read depends_on: %v", diags)
+		}
+	}
+
+	if v := r.RetryBackoff.ValueString(); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse retry backoff: %w", err)
+		}
+		opts.Retry.Backoff = d
+	}
+
+	opts.Wait, err = waitOf(r.WaitTimeout, r.WaitInterval, r.WaitQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	if src != nil && r.Resume.ValueBool() {
+		dg, err := src.Digest()
+		if err != nil {
+			return nil, err
+		}
+		opts.Checkpoint = dg
+	}
+
+	return pipeline.NewDestination(ctx, addr, opts)
+}
+
+type ResourcePipelineTLS struct {
+	Mode       types.String `tfsdk:"mode"`
+	CA         types.String `tfsdk:"ca"`
+	Cert       types.String `tfsdk:"cert"`
+	Key        types.String `tfsdk:"key"`
+	ServerName types.String `tfsdk:"server_name"`
+}
+
+// Apply returns the given database address with the TLS configuration,
+// returns the given address if the TLS configuration is not specified.
+func (r *ResourcePipelineTLS) Apply(addr string) (string, error) {
+	if r == nil {
+		return addr, nil
+	}
+
+	addr, err := sqlx.WithTLS(addr, sqlx.TLS{
+		Mode:       r.Mode.ValueString(),
+		CA:         r.CA.ValueString(),
+		Cert:       r.Cert.ValueString(),
+		Key:        r.Key.ValueString(),
+		ServerName: r.ServerName.ValueString(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot configure TLS: %w", err)
+	}
+
+	return addr, nil
+}
+
+const (
+	tlsModeDescription = `The mode to secure the connections.
+
+  - disable: do not use TLS.
+  - require: use TLS without verifying the server certificate.
+  - verify-ca: use TLS and verify the server certificate is signed by a trusted CA,
+    also verify the host name on SQLServer and Oracle.
+  - verify-full: use TLS and verify the server certificate and host name.`
+	tlsCADescription = `The PEM encoded CA certificates to verify the server certificate,
+the system CA certificates are used if not specified,
+not supported on Oracle, use the WALLET option of address instead.`
+	tlsCertDescription = `The PEM encoded client certificate for mutual TLS, requires key,
+supported on MySQL and Postgres.`
+	tlsKeyDescription = `The PEM encoded private key of the client certificate, requires cert,
+supported on MySQL and Postgres.`
+	tlsServerNameDescription = `The host name to verify the server certificate with,
+default is the host of address, supported on MySQL and SQLServer.`
+)
+
+// tlsModeValidators returns the validators of the TLS mode.
+func tlsModeValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.OneOf(
+			sqlx.TLSModeDisable,
+			sqlx.TLSModeRequire,
+			sqlx.TLSModeVerifyCA,
+			sqlx.TLSModeVerifyFull),
+	}
+}
+
+var (
+	durationRegexp = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`)
+	nonZeroRegexp  = regexp.MustCompile(`[1-9]`)
+)
+
+// durationValidators returns the validators of the Go duration,
+// the given example is shown in the error message,
+// and the zero duration is rejected if the given positive is true.
+func durationValidators(example string, positive bool) []validator.String {
+	vs := []validator.String{
+		stringvalidator.RegexMatches(durationRegexp, "must be a duration, like "+example),
+	}
+
+	if positive {
+		vs = append(vs, stringvalidator.RegexMatches(nonZeroRegexp, "must be a positive duration"))
+	}
+
+	return vs
+}
+
+// tlsAttribute returns the schema of tls attribute for the given end, i.e. source or destination.
+func tlsAttribute(end string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Description: `The TLS configuration of the ` + end + ` database,
+which overrides the tls of connection.`,
+		Attributes: map[string]schema.Attribute{
+			"mode": schema.StringAttribute{
+				Required:    true,
+				Description: tlsModeDescription,
+				Validators:  tlsModeValidators(),
+			},
+			"ca": schema.StringAttribute{
+				Optional:    true,
+				Description: tlsCADescription,
+			},
+			"cert": schema.StringAttribute{
+				Optional:    true,
+				Description: tlsCertDescription,
+			},
+			"key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: tlsKeyDescription,
+			},
+			"server_name": schema.StringAttribute{
+				Optional:    true,
+				Description: tlsServerNameDescription,
+			},
+		},
+	}
+}
+
+// waitOf returns the sqlx.Wait of the given attributes.
+func waitOf(timeout, interval, query types.String) (sqlx.Wait, error) {
+	w := sqlx.Wait{
+		Query: query.ValueString(),
+	}
+
+	if v := timeout.ValueString(); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return w, fmt.Errorf("cannot parse wait timeout: %w", err)
+		}
+
+		if d <= 0 {
+			return w, fmt.Errorf("cannot wait with non-positive timeout %q", v)
+		}
+		w.Timeout = d
+	}
+
+	if v := interval.ValueString(); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return w, fmt.Errorf("cannot parse wait interval: %w", err)
+		}
+
+		if d <= 0 {
+			return w, fmt.Errorf("cannot wait with non-positive interval %q", v)
+		}
+		w.Interval = d
+	}
+
+	return w, nil
+}
+
+// resolveAddress returns the given address,
+// or the address of the named connection declared in provider.
+func resolveAddress(cfg *ProviderConfig, addr, conn types.String) (string, error) {
+	if conn.ValueString() == "" {
+		return addr.ValueString(), nil
+	}
+
+	return cfg.Address(conn.ValueString())
+}
+
+// endpointAttributes returns the given attributes of the given end, i.e. source or destination,
+// with the structured attributes to build the database address.
+func endpointAttributes(end string, attrs map[string]schema.Attribute) map[string]schema.Attribute {
+	requiresDriver := stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("driver"))
+
+	attrs["driver"] = schema.StringAttribute{
+		Optional: true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+		Validators: []validator.String{
+			stringvalidator.OneOf(
+				"mysql", "maria", "mariadb",
+				"postgres", "postgresql",
+				"oracle",
+				"mssql", "sqlserver"),
+			stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("host")),
+		},
+		Description: `The driver of ` + end + ` database, conflicts with address and connection,
+choose from mysql, maria, mariadb, postgres, postgresql, oracle, mssql or sqlserver.`,
+	}
+	attrs["host"] = schema.StringAttribute{
+		Optional: true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+		Validators: []validator.String{
+			requiresDriver,
+		},
+		Description: `The host of ` + end + ` database, requires driver.`,
+	}
+	attrs["port"] = schema.Int64Attribute{
+		Optional: true,
+		PlanModifiers: []planmodifier.Int64{
+			int64planmodifier.RequiresReplace(),
+		},
+		Validators: []validator.Int64{
+			int64validator.Between(1, 65535),
+			int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("driver")),
+		},
+		Description: `The port of ` + end + ` database, requires driver, default is the port of driver.`,
+	}
+	attrs["username"] = schema.StringAttribute{
+		Optional: true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+		Validators: []validator.String{
+			requiresDriver,
+		},
+		Description: `The username to log in ` + end + ` database, requires driver.`,
+	}
+	attrs["password"] = schema.StringAttribute{
+		Optional:  true,
+		Sensitive: true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+		Validators: []validator.String{
+			requiresDriver,
+		},
+		Description: `The password to log in ` + end + ` database, requires driver,
+which needs no escaping.`,
+	}
+	attrs["database"] = schema.StringAttribute{
+		Optional: true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+		Validators: []validator.String{
+			requiresDriver,
+		},
+		Description: `The database name of ` + end + ` database, requires driver,
+which is the service name of oracle.`,
+	}
+	attrs["params"] = schema.MapAttribute{
+		Optional:    true,
+		ElementType: types.StringType,
+		PlanModifiers: []planmodifier.Map{
+			mapplanmodifier.RequiresReplace(),
+		},
+		Validators: []validator.Map{
+			mapvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("driver")),
+		},
+		Description: `The driver parameters of ` + end + ` database, requires driver,
+like {sslmode = "disable"} of postgres.`,
+	}
+
+	return attrs
+}
+
+const (
+	OnDestroyNone                  = "none"
+	OnDestroyTruncateTouchedTables = "truncate_touched_tables"
+	OnDestroyDropTouchedTables     = "drop_touched_tables"
+)
+
+const (
+	DriftDetectionNone     = "none"
+	DriftDetectionRowCount = "row_count"
+	DriftDetectionChecksum = "checksum"
+)
+
+var (
+	rejectedStatementAttrTypes = map[string]attr.Type{
+		"line":  types.Int64Type,
+		"error": types.StringType,
+	}
+	rejectedAttrTypes = map[string]attr.Type{
+		"count":      types.Int64Type,
+		"statements": types.ListType{ElemType: types.ObjectType{AttrTypes: rejectedStatementAttrTypes}},
+	}
+)
+
+// rejectedOf returns the value of rejected attribute from the given rejection.
+func rejectedOf(rej pipeline.Rejection) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	stmts := make([]attr.Value, 0, len(rej.Rejects))
+
+	for _, rj := range rej.Rejects {
+		v, d := types.ObjectValue(rejectedStatementAttrTypes, map[string]attr.Value{
+			"line":  types.Int64Value(int64(rj.Line)),
+			"error": types.StringValue(rj.Error),
+		})
+		diags.Append(d...)
+
+		stmts = append(stmts, v)
+	}
+
+	l, d := types.ListValue(types.ObjectType{AttrTypes: rejectedStatementAttrTypes}, stmts)
+	diags.Append(d...)
+
+	if diags.HasError() {
+		return types.ObjectNull(rejectedAttrTypes), diags
+	}
+
+	o, d := types.ObjectValue(rejectedAttrTypes, map[string]attr.Value{
+		"count":      types.Int64Value(int64(rej.Count)),
+		"statements": l,
+	})
+	diags.Append(d...)
+
+	return o, diags
+}
+
+var statsAttrTypes = map[string]attr.Type{
+	"statements":       types.MapType{ElemType: types.Int64Type},
+	"insert_rows":      types.MapType{ElemType: types.Int64Type},
+	"batches":          types.Int64Type,
+	"bytes_read":       types.Int64Type,
+	"ignored":          types.Int64Type,
+	"skipped":          types.Int64Type,
+	"retries":          types.Int64Type,
+	"peak_buffer_rows": types.Int64Type,
+}
+
+// statsOf returns the value of stats attribute from the given stats and the read bytes of source.
+func statsOf(st pipeline.Stats, bytesRead int64) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	counts := func(m map[string]int) types.Map {
+		es := make(map[string]attr.Value, len(m))
+		for k, v := range m {
+			es[k] = types.Int64Value(int64(v))
+		}
+
+		v, d := types.MapValue(types.Int64Type, es)
+		diags.Append(d...)
+
+		return v
+	}
+
+	stmts := counts(st.Statements)
+	rows := counts(st.InsertRows)
+
+	if diags.HasError() {
+		return types.ObjectNull(statsAttrTypes), diags
+	}
+
+	o, d := types.ObjectValue(statsAttrTypes, map[string]attr.Value{
+		"statements":       stmts,
+		"insert_rows":      rows,
+		"batches":          types.Int64Value(int64(st.Batches)),
+		"bytes_read":       types.Int64Value(bytesRead),
+		"ignored":          types.Int64Value(int64(st.Ignored)),
+		"skipped":          types.Int64Value(int64(st.Skipped)),
+		"retries":          types.Int64Value(int64(st.Retries)),
+		"peak_buffer_rows": types.Int64Value(int64(st.PeakBufferRows)),
+	})
+	diags.Append(d...)
+
+	return o, diags
 }
 
+const (
+	privateKeyTouchedTables = "touched_tables"
+	privateKeyCreatedTables = "created_tables"
+	privateKeyFingerprints  = "fingerprints"
+)
+
 type ResourcePipeline struct {
-	ID          types.String                `tfsdk:"id"`
-	Source      ResourcePipelineSource      `tfsdk:"source"`
-	Destination ResourcePipelineDestination `tfsdk:"destination"`
-	Timeouts    timeouts.Value              `tfsdk:"timeouts"`
-	Cost        types.String                `tfsdk:"cost"`
+	ID               types.String                `tfsdk:"id"`
+	Source           ResourcePipelineSource      `tfsdk:"source"`
+	Destination      ResourcePipelineDestination `tfsdk:"destination"`
+	OnDestroy        types.String                `tfsdk:"on_destroy"`
+	DriftDetection   types.String                `tfsdk:"drift_detection"`
+	DryRun           types.Bool                  `tfsdk:"dry_run"`
+	ProgressInterval types.String                `tfsdk:"progress_interval"`
+	ProgressPath     types.String                `tfsdk:"progress_path"`
+	ReportPath       types.String                `tfsdk:"report_path"`
+	Before           types.List                  `tfsdk:"before"`
+	After            types.List                  `tfsdk:"after"`
+	Timeouts         timeouts.Value              `tfsdk:"timeouts"`
+	Cost             types.String                `tfsdk:"cost"`
+	Rejected         types.Object                `tfsdk:"rejected"`
+	Stats            types.Object                `tfsdk:"stats"`
+
+	config *ProviderConfig
 }
 
 func (r ResourcePipeline) Corrupted() bool {
@@ -66,19 +651,69 @@ func (r ResourcePipeline) Corrupted() bool {
 
 func (r ResourcePipeline) Equal(l ResourcePipeline) bool {
 	return r.Source.Address.Equal(l.Source.Address) &&
+		r.Source.Connection.Equal(l.Source.Connection) &&
 		r.Destination.Address.Equal(l.Destination.Address) &&
-		r.Destination.Salt.Equal(l.Destination.Salt)
+		r.Destination.Connection.Equal(l.Destination.Connection) &&
+		r.Source.Key() == l.Source.Key() &&
+		r.Destination.Key() == l.Destination.Key() &&
+		r.Destination.Salt.Equal(l.Destination.Salt) &&
+		r.Before.Equal(l.Before) &&
+		r.After.Equal(l.After) &&
+		r.DryRun.Equal(l.DryRun)
 }
 
 func (r ResourcePipeline) Hash() string {
+	// The blank connections, endpoints and hooks keep the hash of the address only pipelines.
 	return strx.Sum(
 		r.Source.Address.ValueString(),
+		r.Source.Connection.ValueString(),
 		r.Destination.Address.ValueString(),
-		r.Destination.Salt.ValueString())
+		r.Destination.Connection.ValueString(),
+		r.Destination.Salt.ValueString(),
+		r.Source.Key(),
+		r.Destination.Key(),
+		hooksKey(r.Before),
+		hooksKey(r.After))
+}
+
+// hooksKey returns the key of the given hooks, which is blank if no hooks.
+func hooksKey(l types.List) string {
+	es := l.Elements()
+	if len(es) == 0 {
+		return ""
+	}
+
+	ss := make([]string, 0, len(es))
+	for i := range es {
+		ss = append(ss, es[i].String())
+	}
+
+	return strx.Sum(ss...)
 }
 
 func NewResourcePipeline() resource.Resource {
-	return ResourcePipeline{}
+	return &ResourcePipeline{}
+}
+
+func (r *ResourcePipeline) Configure(
+	ctx context.Context,
+	req resource.ConfigureRequest,
+	resp *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	cfg, ok := req.ProviderData.(*ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Invalid Provider Data",
+			fmt.Sprintf("Cannot configure with unexpected %T provider data", req.ProviderData))
+
+		return
+	}
+
+	r.config = cfg
 }
 
 func (r ResourcePipeline) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -94,12 +729,18 @@ func (r ResourcePipeline) Schema(ctx context.Context, req resource.SchemaRequest
 			},
 			"source": schema.SingleNestedAttribute{
 				Required: true,
-				Attributes: map[string]schema.Attribute{
+				Attributes: endpointAttributes("source", map[string]schema.Attribute{
 					"address": schema.StringAttribute{
-						Required: true,
+						Optional:  true,
+						Sensitive: true,
 						PlanModifiers: []planmodifier.String{
 							stringplanmodifier.RequiresReplace(),
 						},
+						Validators: []validator.String{
+							stringvalidator.ExactlyOneOf(
+								path.MatchRelative().AtParent().AtName("connection"),
+								path.MatchRelative().AtParent().AtName("driver")),
+						},
 						Description: `The address of source, which to provide the dataset, 
 choose from local/remote SQL file or database.
 
@@ -116,25 +757,61 @@ choose from local/remote SQL file or database.
 	  - oracle://[username:[password]@][address][:port][/service][?param1=value1&...]
 	  - mssql|sqlserver://[username:[password]@][address][:port][/instance][?database=dbname&param1=value1&...]`,
 					},
+					"connection": schema.StringAttribute{
+						Optional: true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+						Description: `The name of connection declared in provider, conflicts with address and driver.`,
+					},
 					"conn_max": schema.Int64Attribute{
-						Optional:    true,
-						Computed:    true,
-						Default:     int64default.StaticInt64(5),
-						Description: `The maximum connections of source database.`,
+						Optional: true,
+						Computed: true,
+						Description: `The maximum connections of source database,
+default is the conn_max of the connection or provider defaults, or 5.`,
 						Validators: []validator.Int64{
 							int64validator.AtLeast(1),
 						},
 					},
-				},
+					"wait_timeout": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString("1m"),
+						Description: `The maximum duration to wait for the source database to be ready,
+in form of Go duration, like 30s or 10m.`,
+						Validators: durationValidators("30s or 10m", true),
+					},
+					"wait_interval": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString("5s"),
+						Description: `The duration between the detections of the source database readiness,
+in form of Go duration, like 1s or 10s.`,
+						Validators: durationValidators("1s or 10s", true),
+					},
+					"wait_query": schema.StringAttribute{
+						Optional: true,
+						Description: `The query to detect the source database readiness,
+the database is ready if the query returns any row,
+like SELECT 1 FROM pg_database WHERE datname = 'byteset', pings the database if not specified.`,
+					},
+					"tls": tlsAttribute("source"),
+				}),
 			},
 			"destination": schema.SingleNestedAttribute{
 				Required: true,
-				Attributes: map[string]schema.Attribute{
+				Attributes: endpointAttributes("destination", map[string]schema.Attribute{
 					"address": schema.StringAttribute{
-						Required: true,
+						Optional:  true,
+						Sensitive: true,
 						PlanModifiers: []planmodifier.String{
 							stringplanmodifier.RequiresReplace(),
 						},
+						Validators: []validator.String{
+							stringvalidator.ExactlyOneOf(
+								path.MatchRelative().AtParent().AtName("connection"),
+								path.MatchRelative().AtParent().AtName("driver")),
+						},
 						Description: `The address of destination database, which to receive the dataset.
 
   - Database address format:
@@ -144,20 +821,27 @@ choose from local/remote SQL file or database.
 	  - oracle://[username:[password]@][address][:port][/service][?param1=value1&...]
 	  - mssql|sqlserver://[username:[password]@][address][:port][/instance][?database=dbname&param1=value1&...]`,
 					},
+					"connection": schema.StringAttribute{
+						Optional: true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+						Description: `The name of connection declared in provider, conflicts with address and driver.`,
+					},
 					"conn_max": schema.Int64Attribute{
-						Optional:    true,
-						Computed:    true,
-						Default:     int64default.StaticInt64(5),
-						Description: `The maximum opening connectors of destination database.`,
+						Optional: true,
+						Computed: true,
+						Description: `The maximum opening connectors of destination database,
+default is the conn_max of the connection or provider defaults, or 5.`,
 						Validators: []validator.Int64{
 							int64validator.AtLeast(1),
 						},
 					},
 					"batch_cap": schema.Int64Attribute{
-						Optional:    true,
-						Computed:    true,
-						Default:     int64default.StaticInt64(500),
-						Description: `The maximum value statement number for once insert statements.`,
+						Optional: true,
+						Computed: true,
+						Description: `The maximum value statement number for once insert statements,
+default is the batch_cap of provider defaults, or 500.`,
 						Validators: []validator.Int64{
 							int64validator.AtLeast(1),
 						},
@@ -170,79 +854,769 @@ choose from local/remote SQL file or database.
 						Description: `The salt assist calculating the destination database has changed 
 but the address not, like the database Terraform Managed Resource ID.`,
 					},
-				},
-			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
-				Create: true,
-				Update: true,
-			}),
-			"cost": schema.StringAttribute{
-				Computed:    true,
-				Description: `The time spent on this transfer.`,
-			},
-		},
-	}
-}
-
-func (r ResourcePipeline) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	plan := r
-
-	if plan.ID.IsNull() {
-		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+					"resume": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  booldefault.StaticBool(false),
+						Description: `Record the progress into the byteset_checkpoints table of destination database,
+and skip the applied statements at the next running,
+the inserting statements are flushed in one transaction with the progress,
+the remote source file is identified by its ETag or Last-Modified, otherwise by its content.`,
+					},
+					"bulk": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  booldefault.StaticBool(false),
+						Description: `Load the inserting rows of literal values with the fastest ingestion protocol,
+COPY FROM STDIN for Postgres, LOAD DATA LOCAL INFILE for MySQL and bulk copy for SQLServer,
+insert the rows as usual if the database does not allow, like the local_infile of MySQL server is off.
+The rows are inserted instead if failed to load, like MySQL skips any duplicate row or raises any warning,
+the columns must be specified on SQLServer.`,
+					},
+					"parallel_tables": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  booldefault.StaticBool(false),
+						Description: `Stage the inserting statements by table into a temporary file
+until the next non-inserting statement, and then load the independent tables concurrently across conn_max connections,
+a table is loaded after the tables it references by foreign key or declared in depends_on,
+the tables are loaded in sequence if the foreign keys cannot be detected, besides the depends_on.
+Ignored if resume is true.`,
+					},
+					"depends_on": schema.MapAttribute{
+						Optional:    true,
+						ElementType: types.ListType{ElemType: types.StringType},
+						Description: `The extra dependencies of tables for parallel_tables,
+the key is the table name, and the value is the table names it depends on,
+like { orders = ["customers", "products"] }.`,
+					},
+					"atomic": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  booldefault.StaticBool(false),
+						Description: `Execute the whole pipeline in one transaction of one connection,
+which is rolled back if any statement fails or the applying times out,
+the transaction control statements of the source are ignored, and resume, parallel_tables and bulk are ignored.
+The DDL is included on Postgres and SQLServer, but commits the transaction implicitly on MySQL,
+not supported on Oracle.`,
+					},
+					"on_error": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString(pipeline.OnErrorAbort),
+						Description: `The way to handle the failed statement, choose from the following ways.
 
-		if resp.Diagnostics.HasError() {
-			return
-		}
+  - abort: stop the pipeline.
+  - continue: reject the failed statement and carry on,
+    the failed value tuple of a batched inserting statement is found out by re-executing in halves
+    and rejected alone, the other statements of a transaction are not rejected on Postgres.
 
-		plan.ID = types.StringValue(plan.Hash())
+The failed value tuple is reported with its source line number in both ways.`,
+						Validators: []validator.String{
+							stringvalidator.OneOf(
+								pipeline.OnErrorAbort,
+								pipeline.OnErrorContinue),
+						},
+					},
+					"max_errors": schema.Int64Attribute{
+						Optional: true,
+						Computed: true,
+						Default:  int64default.StaticInt64(0),
+						Description: `The maximum rejected statements if on_error is continue,
+stop the pipeline once exceeds, 0 means no limit.`,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"reject_path": schema.StringAttribute{
+						Optional: true,
+						Description: `The local file to write the rejected statements in if on_error is continue,
+each statement follows a comment of its source line number and error,
+which can be piped again after fixing.`,
+					},
+					"fast_load": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  booldefault.StaticBool(false),
+						Description: `Disable the constraints and triggers during loading, and validate the integrity at the end,
+set FOREIGN_KEY_CHECKS and UNIQUE_CHECKS to 0 of each MySQL session,
+set session_replication_role to replica of each Postgres session, which requires superuser,
+disable the constraints and triggers of each written table on SQLServer and the foreign keys and triggers on Oracle.
+The foreign keys and unique keys of the written tables are validated on MySQL,
+the foreign keys of the written tables are validated on Postgres,
+the constraints are enabled with validating on SQLServer and Oracle.
+Disabling the constraints commits the transaction implicitly on Oracle,
+so that atomic or writing a new table inside the transaction of source are refused on Oracle.`,
+					},
+					"retry_attempts": schema.Int64Attribute{
+						Optional: true,
+						Computed: true,
+						Default:  int64default.StaticInt64(3),
+						Description: `The maximum retrying times of the transient errors,
+like deadlock, serialization failure or dropped connection, 0 to disable,
+the statements inside a transaction are never retried,
+neither are the statements changing data if the connection is dropped after sending, which may have been applied.`,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"retry_backoff": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString("1s"),
+						Description: `The waiting duration before the first retrying,
+which is doubled at each retrying and up to 30s, in form of Go duration, like 500ms or 2s.`,
+						Validators: durationValidators("500ms or 2s", true),
+					},
+					"wait_timeout": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString("1m"),
+						Description: `The maximum duration to wait for the destination database to be ready,
+in form of Go duration, like 30s or 10m.`,
+						Validators: durationValidators("30s or 10m", true),
+					},
+					"wait_interval": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString("5s"),
+						Description: `The duration between the detections of the destination database readiness,
+in form of Go duration, like 1s or 10s.`,
+						Validators: durationValidators("1s or 10s", true),
+					},
+					"wait_query": schema.StringAttribute{
+						Optional: true,
+						Description: `The query to detect the destination database readiness,
+the database is ready if the query returns any row,
+like SELECT 1 FROM pg_database WHERE datname = 'byteset', pings the database if not specified.`,
+					},
+					"tls": tlsAttribute("destination"),
+				}),
+			},
+			"on_destroy": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(OnDestroyNone),
+				Description: `The action to clean up the destination database when destroying,
+choose from the following actions or a SQL file address.
+
+  - Actions:
+	  - none: do nothing.
+	  - truncate_touched_tables: remove all rows of the tables created or written by the source.
+	  - drop_touched_tables: remove the tables created by the source,
+	    and remove all rows of the tables only written by the source,
+	    the table created with IF NOT EXISTS is regarded as written.
+
+  - Local/Remote SQL file format:
+	  - file:///path/to/filename
+	  - http(s)://...
+	  - raw://...
+	  - raw+base64://...`,
+				Validators: []validator.String{
+					stringvalidator.Any(
+						stringvalidator.OneOf(
+							OnDestroyNone,
+							OnDestroyTruncateTouchedTables,
+							OnDestroyDropTouchedTables),
+						stringvalidator.RegexMatches(
+							regexp.MustCompile(`^(file|https?|raw|raw\+base64)://`),
+							"must be a local/remote SQL file address"),
+					),
+				},
+			},
+			"drift_detection": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(DriftDetectionNone),
+				Description: `The way to detect the changes of the tables created or written by the source,
+the fingerprint of each table is recorded after applying and recalculated at refreshing,
+recreate the pipeline if the fingerprint changed.
+
+  - none: do nothing.
+  - row_count: count the rows of each table.
+  - checksum: count the rows and checksum the rows of each table,
+    only counting on Oracle.`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						DriftDetectionNone,
+						DriftDetectionRowCount,
+						DriftDetectionChecksum),
+				},
+			},
+			"progress_interval": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("30s"),
+				Description: `The interval to report the progress at info level,
+including the read bytes out of the total, the statements and rows per second and the ETA,
+in form of Go duration, like 10s or 1m, 0s disables reporting except the final progress.`,
+				Validators: durationValidators("10s or 1m", false),
+			},
+			"progress_path": schema.StringAttribute{
+				Optional: true,
+				Description: `The local file to write the progress in JSON lines at each reporting,
+which is truncated before piping, the final progress is always written.`,
+			},
+			"before": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: `The SQL statements or the local/remote SQL file addresses to execute against the destination
+before the source, like creating schemas or extensions,
+in the same session of the source if inside a transaction,
+the statements are executed verbatim even if cannot be classified, like VACUUM,
+the pipeline is applied again once changed.
+
+  - Local/Remote SQL file format:
+	  - file:///path/to/filename
+	  - http(s)://...
+	  - raw://...
+	  - raw+base64://...`,
+			},
+			"after": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: `The SQL statements or the local/remote SQL file addresses to execute against the destination
+after the source, like refreshing materialized views, resetting sequences or analyzing tables,
+in the same session of the source if inside a transaction,
+the statements are executed verbatim even if cannot be classified, like VACUUM,
+the pipeline is applied again once changed.
+
+  - Local/Remote SQL file format:
+	  - file:///path/to/filename
+	  - http(s)://...
+	  - raw://...
+	  - raw+base64://...`,
+			},
+			"report_path": schema.StringAttribute{
+				Optional: true,
+				Description: `The local file to write the report in JSON after each transfer,
+including the starting and ending time, the redacted source and destination, the digest of the source,
+the statistics, the inserted rows and inserting duration of each table, the rejected and ignored statements,
+and the error if failed.`,
+			},
+			"dry_run": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: `Validate the source against the destination database without changing any data,
+the inserting, updating and deleting statements are prepared on MySQL/Postgres,
+compiled on SQLServer and explained on Oracle, the others are only parsed,
+the problems are reported with the source line number at planning and applying.`,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+			"cost": schema.StringAttribute{
+				Computed:    true,
+				Description: `The time spent on this transfer.`,
+			},
+			"rejected": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: `The summary of the rejected statements if on_error is continue.`,
+				Attributes: map[string]schema.Attribute{
+					"count": schema.Int64Attribute{
+						Computed:    true,
+						Description: `The total number of the rejected statements.`,
+					},
+					"statements": schema.ListNestedAttribute{
+						Computed:    true,
+						Description: `The first 10 rejected statements.`,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"line": schema.Int64Attribute{
+									Computed: true,
+									Description: `The source line number of the statement,
+0 if unknown.`,
+								},
+								"error": schema.StringAttribute{
+									Computed:    true,
+									Description: `The error of the statement.`,
+								},
+							},
+						},
+					},
+				},
+			},
+			"stats": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: `The statistics of the latest transfer.`,
+				Attributes: map[string]schema.Attribute{
+					"statements": schema.MapAttribute{
+						Computed:    true,
+						ElementType: types.Int64Type,
+						Description: `The number of the executed statements by type, i.e. ddl, dml, tcl and dcl.`,
+					},
+					"insert_rows": schema.MapAttribute{
+						Computed:    true,
+						ElementType: types.Int64Type,
+						Description: `The number of the inserted rows by table.`,
+					},
+					"batches": schema.Int64Attribute{
+						Computed:    true,
+						Description: `The number of the flushed insert batches and bulk loads.`,
+					},
+					"bytes_read": schema.Int64Attribute{
+						Computed:    true,
+						Description: `The number of the bytes read from the source file.`,
+					},
+					"ignored": schema.Int64Attribute{
+						Computed: true,
+						Description: `The number of the ignored statements,
+i.e. the unknown statements and the transaction control statements if atomic.`,
+					},
+					"skipped": schema.Int64Attribute{
+						Computed:    true,
+						Description: `The number of the statements skipped by resuming from the checkpoint.`,
+					},
+					"retries": schema.Int64Attribute{
+						Computed:    true,
+						Description: `The number of the retrying of the transient errors.`,
+					},
+					"peak_buffer_rows": schema.Int64Attribute{
+						Computed:    true,
+						Description: `The maximum number of the rows buffered in memory.`,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r ResourcePipeline) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	// Skip destroying.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan ResourcePipeline
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, conn := range []struct {
+		path path.Path
+		name types.String
+	}{
+		{path: path.Root("source").AtName("connection"), name: plan.Source.Connection},
+		{path: path.Root("destination").AtName("connection"), name: plan.Destination.Connection},
+	} {
+		if conn.name.IsUnknown() || conn.name.ValueString() == "" {
+			continue
+		}
+
+		if _, err := r.config.Address(conn.name.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				conn.path,
+				"Invalid Connection",
+				"Cannot resolve connection: "+err.Error())
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fill the unspecified values with the provider defaults.
+	if plan.Source.ConnMax.IsUnknown() {
+		plan.Source.ConnMax = types.Int64Value(r.config.ConnMax(plan.Source.Connection.ValueString()))
+	}
+
+	if plan.Destination.ConnMax.IsUnknown() {
+		plan.Destination.ConnMax = types.Int64Value(r.config.ConnMax(plan.Destination.Connection.ValueString()))
+	}
 
-		timeout, diags := r.Timeouts.Create(ctx, 30*time.Minute)
+	if plan.Destination.BatchCap.IsUnknown() {
+		plan.Destination.BatchCap = types.Int64Value(r.config.BatchCap())
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || !plan.DryRun.ValueBool() || !plan.Known() {
+		return
+	}
+
+	// Validate the creating or changing pipeline at planning.
+	if !req.State.Raw.IsNull() {
+		var state ResourcePipeline
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() || plan.Equal(state) {
+			return
+		}
+	}
+
+	plan.config = r.config
+	resp.Diagnostics.Append(plan.Validate(ctx)...)
+}
+
+// Known returns true if the source and destination are known.
+func (r ResourcePipeline) Known() bool {
+	vs := []attr.Value{
+		r.Source.Address,
+		r.Source.Connection,
+		r.Source.Driver,
+		r.Source.Host,
+		r.Source.Port,
+		r.Source.Username,
+		r.Source.Password,
+		r.Source.Database,
+		r.Source.Params,
+		r.Destination.Address,
+		r.Destination.Connection,
+		r.Destination.Driver,
+		r.Destination.Host,
+		r.Destination.Port,
+		r.Destination.Username,
+		r.Destination.Password,
+		r.Destination.Database,
+		r.Destination.Params,
+	}
+
+	for i := range vs {
+		if vs[i].IsUnknown() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ReflectSource returns the Source of the source,
+// which is piped after the before hooks and before the after hooks.
+func (r ResourcePipeline) ReflectSource(ctx context.Context) (pipeline.Source, error) {
+	var before, after []string
+
+	if !r.Before.IsNull() && !r.Before.IsUnknown() {
+		diags := r.Before.ElementsAs(ctx, &before, false)
+		if diags.HasError() {
+			return nil, fmt.Errorf("cannot read before: %v", diags)
+		}
+	}
+
+	if !r.After.IsNull() && !r.After.IsUnknown() {
+		diags := r.After.ElementsAs(ctx, &after, false)
+		if diags.HasError() {
+			return nil, fmt.Errorf("cannot read after: %v", diags)
+		}
+	}
+
+	srcs := make([]pipeline.Source, 0, len(before)+1+len(after))
+
+	closeAll := func() {
+		for i := range srcs {
+			_ = srcs[i].Close()
+		}
+	}
+
+	for i := range before {
+		hk, err := pipeline.NewHook(ctx, before[i])
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("cannot reflect from before hook %d: %w", i, err)
+		}
+
+		srcs = append(srcs, hk)
+	}
+
+	src, err := r.Source.Reflect(ctx, r.config)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	srcs = append(srcs, src)
+
+	for i := range after {
+		hk, err := pipeline.NewHook(ctx, after[i])
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("cannot reflect from after hook %d: %w", i, err)
+		}
+
+		srcs = append(srcs, hk)
+	}
+
+	return pipeline.Chain(srcs...), nil
+}
+
+// Report writes the report of the transfer started at the given time and ended with the given error,
+// it does nothing if report_path is blank.
+func (r ResourcePipeline) Report(
+	ctx context.Context,
+	src pipeline.Source,
+	dst pipeline.Destination,
+	start time.Time,
+	err error,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	p := r.ReportPath.ValueString()
+	if p == "" {
+		return diags
+	}
+
+	// The addresses have been resolved at reflecting.
+	srcAddr, _ := r.Source.Resolve(ctx, r.config)
+	dstAddr, _ := r.Destination.Resolve(ctx, r.config)
+
+	rpt := pipeline.NewReport(src, srcAddr, dst, dstAddr, start, err)
+
+	if werr := pipeline.WriteReport(p, rpt); werr != nil {
+		diags.AddAttributeWarning(
+			path.Root("report_path"),
+			"Failed Report",
+			"Cannot write report: "+werr.Error())
+	}
+
+	return diags
+}
+
+// Validate validates the source against the destination without changing any data.
+func (r ResourcePipeline) Validate(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	src, err := r.ReflectSource(ctx)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("source"),
+			"Invalid Source",
+			"Cannot reflect from source: "+err.Error())
+
+		return diags
+	}
+
+	defer func() { _ = src.Close() }()
+
+	addr, err := r.Destination.Resolve(ctx, r.config)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("destination"),
+			"Invalid Destination",
+			"Cannot reflect from destination: "+err.Error())
+
+		return diags
+	}
+
+	addr, err = r.Destination.TLS.Apply(addr)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("destination"),
+			"Invalid Destination",
+			"Cannot reflect from destination: "+err.Error())
+
+		return diags
+	}
+
+	w, err := waitOf(r.Destination.WaitTimeout, r.Destination.WaitInterval, r.Destination.WaitQuery)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("destination"),
+			"Invalid Destination",
+			"Cannot reflect from destination: "+err.Error())
+
+		return diags
+	}
+
+	vld, err := pipeline.NewValidation(ctx, addr, w)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("destination"),
+			"Invalid Destination",
+			"Cannot reflect from destination: "+err.Error())
+
+		return diags
+	}
+
+	defer func() { _ = vld.Close() }()
+
+	if err = src.Pipe(ctx, vld); err != nil {
+		diags.AddError(
+			"Failed Validate",
+			"Cannot validate source: "+err.Error())
+
+		return diags
+	}
+
+	for _, p := range vld.Problems {
+		detail := fmt.Sprintf("Line %d: %v\n\n%s", p.Line, p.Error, p.SQL)
+
+		if p.Warning {
+			diags.AddAttributeWarning(path.Root("source"), "Ignored Statement", detail)
+		} else {
+			diags.AddAttributeError(path.Root("source"), "Invalid Statement", detail)
+		}
+	}
+
+	return diags
+}
+
+func (r ResourcePipeline) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	plan := r
+
+	if plan.ID.IsNull() {
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.ID = types.StringValue(plan.Hash())
+		plan.config = r.config
+
+		timeout, diags := plan.Timeouts.Create(ctx, r.config.Timeout())
 		resp.Diagnostics.Append(diags...)
 
 		if resp.Diagnostics.HasError() {
 			return
 		}
 
-		var cancel func()
+		var cancel func()
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		ctx = r.config.Context(ctx)
+	}
+
+	if plan.DryRun.ValueBool() {
+		start := time.Now()
+
+		resp.Diagnostics.Append(plan.Validate(ctx)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.Cost = types.StringValue(time.Since(start).String())
+		plan.Rejected = types.ObjectNull(rejectedAttrTypes)
+		plan.Stats = types.ObjectNull(statsAttrTypes)
+
+		plan.Read(
+			ctx,
+			resource.ReadRequest{
+				State:        resp.State,
+				Private:      resp.Private,
+				ProviderMeta: req.ProviderMeta,
+			},
+			(*resource.ReadResponse)(resp))
+
+		return
+	}
+
+	src, err := plan.ReflectSource(ctx)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source"),
+			"Invalid Source",
+			"Cannot reflect from source: "+err.Error())
+
+		return
+	}
+
+	defer func() { _ = src.Close() }()
+
+	dst, err := plan.Destination.Reflect(ctx, plan.config, src)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("destination"),
+			"Invalid Destination",
+			"Cannot reflect from destination: "+err.Error())
+
+		return
+	}
+
+	defer func() { _ = dst.Close() }()
+
+	popts := pipeline.ProgressOptions{
+		Path: plan.ProgressPath.ValueString(),
+	}
+
+	if v := plan.ProgressInterval.ValueString(); v != "" {
+		popts.Interval, err = time.ParseDuration(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("progress_interval"),
+				"Invalid Progress Interval",
+				"Cannot parse progress interval: "+err.Error())
+
+			return
+		}
+	}
+
+	start := time.Now()
+
+	err = pipeline.Pipe(ctx, src, dst, popts)
+
+	resp.Diagnostics.Append(plan.Report(ctx, src, dst, start, err)...)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed Pipe",
+			"Cannot pipe from source to destination: "+err.Error())
+
+		return
+	}
+	plan.Cost = types.StringValue(time.Since(start).String())
+
+	var diags diag.Diagnostics
+
+	plan.Rejected, diags = rejectedOf(dst.Rejection())
+	resp.Diagnostics.Append(diags...)
 
-		ctx, cancel = context.WithTimeout(ctx, timeout)
-		defer cancel()
+	plan.Stats, diags = statsOf(dst.Stats(), src.BytesRead())
+	resp.Diagnostics.Append(diags...)
+
+	if reason := dst.Stats().BulkDisabled; reason != "" {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("destination").AtName("bulk"),
+			"Bulk Loading Disabled",
+			"Cannot bulk load, inserted the rows instead since then: "+reason)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	src, err := plan.Source.Reflect(ctx)
+	touched, err := json.Marshal(dst.Touched())
 	if err != nil {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("source"),
-			"Invalid Source",
-			"Cannot reflect from source: "+err.Error())
+		resp.Diagnostics.AddError(
+			"Failed Record",
+			"Cannot record touched tables: "+err.Error())
 
 		return
 	}
 
-	defer func() { _ = src.Close() }()
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateKeyTouchedTables, touched)...)
 
-	dst, err := plan.Destination.Reflect(ctx)
+	created, err := json.Marshal(dst.Created())
 	if err != nil {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("destination"),
-			"Invalid Destination",
-			"Cannot reflect from destination: "+err.Error())
+		resp.Diagnostics.AddError(
+			"Failed Record",
+			"Cannot record created tables: "+err.Error())
 
 		return
 	}
 
-	defer func() { _ = dst.Close() }()
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, privateKeyCreatedTables, created)...)
 
-	start := time.Now()
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	if err = src.Pipe(ctx, dst); err != nil {
-		resp.Diagnostics.AddError(
-			"Failed Pipe",
-			"Cannot pipe from source to destination: "+err.Error())
+	resp.Diagnostics.Append(plan.Fingerprint(ctx, dst, dst.Touched(), resp.Private)...)
 
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	plan.Cost = types.StringValue(time.Since(start).String())
 
 	plan.Read(
 		ctx,
@@ -256,13 +1630,16 @@ func (r ResourcePipeline) Create(ctx context.Context, req resource.CreateRequest
 
 func (r ResourcePipeline) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	state := r
+	refreshing := state.ID.IsNull()
 
-	if state.ID.IsNull() {
+	if refreshing {
 		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 		if resp.Diagnostics.HasError() {
 			return
 		}
+
+		state.config = r.config
 	}
 
 	if state.Corrupted() {
@@ -272,11 +1649,212 @@ func (r ResourcePipeline) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	// TODO Record something from Destination.
+	if refreshing {
+		drifted, diags := state.Drifted(ctx, req.Private)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if drifted {
+			tflog.Debug(ctx, "Destination is changed, recreating...")
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
+// Fingerprint records the fingerprints of the given tables in the way of drift_detection,
+// clears the recorded fingerprints if drift_detection is none.
+func (r ResourcePipeline) Fingerprint(
+	ctx context.Context,
+	dst pipeline.Destination,
+	tables []string,
+	private privateStateSetter,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	fps := map[string]string{}
+
+	if dd := r.DriftDetection.ValueString(); dd != "" && dd != DriftDetectionNone && len(tables) != 0 {
+		var err error
+
+		fps, err = dst.Fingerprint(ctx, tables, dd == DriftDetectionChecksum)
+		if err != nil {
+			diags.AddError(
+				"Failed Fingerprint",
+				"Cannot fingerprint destination: "+err.Error())
+
+			return diags
+		}
+	}
+
+	bs, err := json.Marshal(fps)
+	if err != nil {
+		diags.AddError(
+			"Failed Record",
+			"Cannot record fingerprints: "+err.Error())
+
+		return diags
+	}
+
+	diags.Append(private.SetKey(ctx, privateKeyFingerprints, bs)...)
+
+	return diags
+}
+
+// Refingerprint records the fingerprints of the touched tables in the given private state again,
+// which is required once drift_detection changes,
+// otherwise the recorded fingerprints cannot be compared in the new way.
+func (r ResourcePipeline) Refingerprint(
+	ctx context.Context,
+	private privateState,
+	setter privateStateSetter,
+) diag.Diagnostics {
+	var (
+		diags   diag.Diagnostics
+		touched []string
+	)
+
+	bs, ds := private.GetKey(ctx, privateKeyTouchedTables)
+	diags.Append(ds...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	if len(bs) != 0 {
+		if err := json.Unmarshal(bs, &touched); err != nil {
+			diags.AddError(
+				"Invalid Private State",
+				"Cannot decode touched tables: "+err.Error())
+
+			return diags
+		}
+	}
+
+	dd := r.DriftDetection.ValueString()
+	if dd == "" || dd == DriftDetectionNone || len(touched) == 0 {
+		return r.Fingerprint(ctx, nil, nil, setter)
+	}
+
+	dst, err := r.Destination.Reflect(ctx, r.config, nil)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("destination"),
+			"Invalid Destination",
+			"Cannot reflect from destination: "+err.Error())
+
+		return diags
+	}
+
+	defer func() { _ = dst.Close() }()
+
+	return r.Fingerprint(ctx, dst, touched, setter)
+}
+
+// Drifted returns true if the fingerprints recorded in the given private state
+// are different from the destination.
+func (r ResourcePipeline) Drifted(ctx context.Context, private privateState) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	dd := r.DriftDetection.ValueString()
+	if dd == "" || dd == DriftDetectionNone {
+		return false, diags
+	}
+
+	recorded, ds := recordedFingerprints(ctx, private)
+	diags.Append(ds...)
+
+	if diags.HasError() || len(recorded) == 0 {
+		return false, diags
+	}
+
+	dst, err := r.Destination.Reflect(ctx, r.config, nil)
+	if err != nil {
+		// Tolerate the unreachable destination.
+		diags.AddAttributeWarning(
+			path.Root("destination"),
+			"Unreachable Destination",
+			"Cannot detect drift from destination: "+err.Error())
+
+		return false, diags
+	}
+
+	defer func() { _ = dst.Close() }()
+
+	drifted, err := driftedFrom(ctx, dst, recorded, dd == DriftDetectionChecksum)
+	if err != nil {
+		diags.AddAttributeWarning(
+			path.Root("destination"),
+			"Unreachable Destination",
+			"Cannot detect drift from destination: "+err.Error())
+
+		return false, diags
+	}
+
+	return drifted, diags
+}
+
+// recordedFingerprints returns the fingerprints recorded in the given private state.
+func recordedFingerprints(ctx context.Context, private privateState) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	bs, ds := private.GetKey(ctx, privateKeyFingerprints)
+	diags.Append(ds...)
+
+	if diags.HasError() || len(bs) == 0 {
+		return nil, diags
+	}
+
+	var recorded map[string]string
+	if err := json.Unmarshal(bs, &recorded); err != nil {
+		diags.AddError(
+			"Invalid Private State",
+			"Cannot decode fingerprints: "+err.Error())
+
+		return nil, diags
+	}
+
+	return recorded, diags
+}
+
+// driftedFrom returns true if any recorded fingerprint is different from the given destination.
+func driftedFrom(
+	ctx context.Context,
+	dst pipeline.Destination,
+	recorded map[string]string,
+	checksum bool,
+) (bool, error) {
+	tables := make([]string, 0, len(recorded))
+	for t := range recorded {
+		tables = append(tables, t)
+	}
+
+	actual, err := dst.Fingerprint(ctx, tables, checksum)
+	if err != nil {
+		return false, err
+	}
+
+	for t := range recorded {
+		if recorded[t] != actual[t] {
+			tflog.Info(ctx, "Table is drifted", map[string]any{
+				"table":    t,
+				"recorded": recorded[t],
+				"actual":   actual[t],
+			})
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (r ResourcePipeline) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan, state ResourcePipeline
 
@@ -291,8 +1869,9 @@ func (r ResourcePipeline) Update(ctx context.Context, req resource.UpdateRequest
 		tflog.Debug(ctx, "Plan is changed, recreating...")
 
 		plan.ID = types.StringValue(plan.Hash())
+		plan.config = r.config
 
-		timeout, diags := r.Timeouts.Create(ctx, 30*time.Minute)
+		timeout, diags := plan.Timeouts.Update(ctx, r.config.Timeout())
 		resp.Diagnostics.Append(diags...)
 
 		if resp.Diagnostics.HasError() {
@@ -304,6 +1883,8 @@ func (r ResourcePipeline) Update(ctx context.Context, req resource.UpdateRequest
 		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 
+		ctx = r.config.Context(ctx)
+
 		plan.Create(
 			ctx,
 			resource.CreateRequest{
@@ -312,8 +1893,349 @@ func (r ResourcePipeline) Update(ctx context.Context, req resource.UpdateRequest
 				ProviderMeta: req.ProviderMeta,
 			},
 			(*resource.CreateResponse)(resp))
+
+		return
+	}
+
+	// Keep the computed values.
+	plan.ID = state.ID
+	plan.Cost = state.Cost
+	plan.Rejected = state.Rejected
+	plan.Stats = state.Stats
+
+	if !plan.DriftDetection.Equal(state.DriftDetection) {
+		plan.config = r.config
+
+		timeout, diags := plan.Timeouts.Update(ctx, r.config.Timeout())
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		ctx = r.config.Context(ctx)
+
+		resp.Diagnostics.Append(plan.Refingerprint(ctx, req.Private, resp.Private)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
 func (r ResourcePipeline) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ResourcePipeline
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Nothing was changed in dry run.
+	act := state.OnDestroy.ValueString()
+	if act == "" || act == OnDestroyNone || state.DryRun.ValueBool() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Delete(ctx, r.config.Timeout())
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ctx = r.config.Context(ctx)
+
+	dst, err := state.Destination.Reflect(ctx, r.config, nil)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("destination"),
+			"Invalid Destination",
+			"Cannot reflect from destination: "+err.Error())
+
+		return
+	}
+
+	defer func() { _ = dst.Close() }()
+
+	switch act {
+	case OnDestroyTruncateTouchedTables, OnDestroyDropTouchedTables:
+		var touched, created []string
+
+		for _, k := range []struct {
+			key    string
+			name   string
+			tables *[]string
+		}{
+			{key: privateKeyTouchedTables, name: "touched", tables: &touched},
+			{key: privateKeyCreatedTables, name: "created", tables: &created},
+		} {
+			bs, diags := req.Private.GetKey(ctx, k.key)
+			resp.Diagnostics.Append(diags...)
+
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			if len(bs) == 0 {
+				continue
+			}
+
+			if err = json.Unmarshal(bs, k.tables); err != nil {
+				resp.Diagnostics.AddError(
+					"Invalid Private State",
+					"Cannot decode "+k.name+" tables: "+err.Error())
+
+				return
+			}
+		}
+
+		if act == OnDestroyTruncateTouchedTables {
+			err = dst.Truncate(ctx, touched)
+			break
+		}
+
+		// Drop the tables created by the source,
+		// and truncate the tables it only wrote to, which may exist before.
+		err = dst.Drop(ctx, created)
+		if err == nil {
+			err = dst.Truncate(ctx, writtenOnly(touched, created))
+		}
+	default:
+		var src pipeline.Source
+
+		src, err = pipeline.NewSource(ctx, act, 1, sqlx.Wait{})
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("on_destroy"),
+				"Invalid Source",
+				"Cannot reflect from source: "+err.Error())
+
+			return
+		}
+
+		defer func() { _ = src.Close() }()
+
+		err = src.Pipe(ctx, dst)
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed Clean",
+			"Cannot clean up destination: "+err.Error())
+	}
+}
+
+// writtenOnly returns the given touched tables which are not in the given created tables.
+func writtenOnly(touched, created []string) []string {
+	cs := make(map[string]struct{}, len(created))
+	for _, t := range created {
+		cs[t] = struct{}{}
+	}
+
+	var r []string
+
+	for _, t := range touched {
+		if _, ok := cs[t]; !ok {
+			r = append(r, t)
+		}
+	}
+
+	return r
+}
+
+// ResourcePipelineImport is the JSON format of the import ID.
+type ResourcePipelineImport struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Salt        string `json:"salt,omitempty"`
+}
+
+func (r ResourcePipeline) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	var imp ResourcePipelineImport
+
+	err := json.Unmarshal([]byte(req.ID), &imp)
+	if err != nil || imp.Source == "" || imp.Destination == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			`Import ID must be in JSON format, like {"source":"...","destination":"...","salt":"..."}, `+
+				`the "salt" is optional.`)
+
+		return
+	}
+
+	salt := types.StringNull()
+	if imp.Salt != "" {
+		salt = types.StringValue(imp.Salt)
+	}
+
+	state := ResourcePipeline{
+		Source: ResourcePipelineSource{
+			Address:      types.StringValue(imp.Source),
+			Connection:   types.StringNull(),
+			Driver:       types.StringNull(),
+			Host:         types.StringNull(),
+			Port:         types.Int64Null(),
+			Username:     types.StringNull(),
+			Password:     types.StringNull(),
+			Database:     types.StringNull(),
+			Params:       types.MapNull(types.StringType),
+			ConnMax:      types.Int64Value(r.config.ConnMax("")),
+			WaitTimeout:  types.StringValue("1m"),
+			WaitInterval: types.StringValue("5s"),
+			WaitQuery:    types.StringNull(),
+		},
+		Destination: ResourcePipelineDestination{
+			Address:        types.StringValue(imp.Destination),
+			Connection:     types.StringNull(),
+			Driver:         types.StringNull(),
+			Host:           types.StringNull(),
+			Port:           types.Int64Null(),
+			Username:       types.StringNull(),
+			Password:       types.StringNull(),
+			Database:       types.StringNull(),
+			Params:         types.MapNull(types.StringType),
+			ConnMax:        types.Int64Value(r.config.ConnMax("")),
+			BatchCap:       types.Int64Value(r.config.BatchCap()),
+			Salt:           salt,
+			Resume:         types.BoolValue(false),
+			RetryAttempts:  types.Int64Value(3),
+			RetryBackoff:   types.StringValue("1s"),
+			Bulk:           types.BoolValue(false),
+			ParallelTables: types.BoolValue(false),
+			DependsOn:      types.MapNull(types.ListType{ElemType: types.StringType}),
+			FastLoad:       types.BoolValue(false),
+			Atomic:         types.BoolValue(false),
+			OnError:        types.StringValue(pipeline.OnErrorAbort),
+			MaxErrors:      types.Int64Value(0),
+			RejectPath:     types.StringNull(),
+			WaitTimeout:    types.StringValue("1m"),
+			WaitInterval:   types.StringValue("5s"),
+			WaitQuery:      types.StringNull(),
+		},
+		OnDestroy:        types.StringValue(OnDestroyNone),
+		DriftDetection:   types.StringValue(DriftDetectionNone),
+		DryRun:           types.BoolValue(false),
+		ProgressInterval: types.StringValue("30s"),
+		Before:           types.ListNull(types.StringType),
+		After:            types.ListNull(types.StringType),
+	}
+	state.ID = types.StringValue(state.Hash())
+
+	// Adopt the seeded destination without piping,
+	// the other attributes are set with defaults.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), state.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("source"), state.Source)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("destination"), state.Destination)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("on_destroy"), state.OnDestroy)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("drift_detection"), state.DriftDetection)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dry_run"), state.DryRun)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("progress_interval"), state.ProgressInterval)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Rebuild the private state from the source,
+	// so that on_destroy and drift_detection can be changed after importing.
+	state.config = r.config
+	resp.Diagnostics.Append(state.Summarize(ctx, resp.Private)...)
+}
+
+// Summarize records the tables touched and created by the source in the given private state,
+// which are summarized from the statements of the source without executing,
+// and clears the recorded fingerprints.
+func (r ResourcePipeline) Summarize(ctx context.Context, private privateStateSetter) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	addr, err := r.Destination.Resolve(ctx, r.config)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("destination"),
+			"Invalid Destination",
+			"Cannot reflect from destination: "+err.Error())
+
+		return diags
+	}
+
+	drv, _, err := sqlx.ParseAddress(addr)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("destination"),
+			"Invalid Destination",
+			"Cannot reflect from destination: "+err.Error())
+
+		return diags
+	}
+
+	ctx, cancel := context.WithTimeout(r.config.Context(ctx), r.config.Timeout())
+	defer cancel()
+
+	src, err := r.Source.Reflect(ctx, r.config)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("source"),
+			"Invalid Source",
+			"Cannot reflect from source: "+err.Error())
+
+		return diags
+	}
+
+	defer func() { _ = src.Close() }()
+
+	sum := pipeline.NewSummary(drv)
+
+	if err = src.Pipe(ctx, sum); err != nil {
+		diags.AddError(
+			"Failed Parse",
+			"Cannot parse source: "+err.Error())
+
+		return diags
+	}
+
+	for _, rc := range []struct {
+		key    string
+		name   string
+		tables []string
+	}{
+		{key: privateKeyTouchedTables, name: "touched", tables: sum.Tables},
+		{key: privateKeyCreatedTables, name: "created", tables: sum.Created},
+	} {
+		tables := rc.tables
+		if tables == nil {
+			tables = []string{}
+		}
+
+		bs, err := json.Marshal(tables)
+		if err != nil {
+			diags.AddError(
+				"Failed Record",
+				"Cannot record "+rc.name+" tables: "+err.Error())
+
+			return diags
+		}
+
+		diags.Append(private.SetKey(ctx, rc.key, bs)...)
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.Append(r.Fingerprint(ctx, nil, nil, private)...)
+
+	return diags
 }
@@ -0,0 +1,94 @@
+package byteset
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seal-io/terraform-provider-byteset/pipeline"
+)
+
+// fingerprintDestination is a pipeline.Destination fingerprints by the counted rows.
+type fingerprintDestination struct {
+	pipeline.Destination
+
+	rows map[string]int
+}
+
+func (d fingerprintDestination) Fingerprint(
+	ctx context.Context,
+	tables []string,
+	checksum bool,
+) (map[string]string, error) {
+	fps := make(map[string]string, len(tables))
+	for _, t := range tables {
+		fps[t] = strconv.Itoa(d.rows[t])
+	}
+
+	return fps, nil
+}
+
+func TestResourcePipeline_drift(t *testing.T) {
+	tc := []struct {
+		name     string
+		mode     string
+		given    map[string]int
+		expected bool
+	}{
+		{
+			name:     "unchanged",
+			mode:     DriftDetectionRowCount,
+			given:    map[string]int{"a": 2, "b": 3},
+			expected: false,
+		},
+		{
+			name:     "changed",
+			mode:     DriftDetectionRowCount,
+			given:    map[string]int{"a": 2, "b": 4},
+			expected: true,
+		},
+		{
+			name:     "dropped",
+			mode:     DriftDetectionRowCount,
+			given:    map[string]int{"a": 2},
+			expected: true,
+		},
+		{
+			name:     "not detected",
+			mode:     DriftDetectionNone,
+			given:    map[string]int{"a": 2, "b": 4},
+			expected: false,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.TODO()
+			r := ResourcePipeline{DriftDetection: types.StringValue(c.mode)}
+			private := privateStateMap{}
+
+			// Fingerprint at creating.
+			created := fingerprintDestination{rows: map[string]int{"a": 2, "b": 3}}
+
+			diags := r.Fingerprint(ctx, created, []string{"a", "b"}, private)
+			if !assert.False(t, diags.HasError(), diags) {
+				return
+			}
+
+			// Detect at refreshing,
+			// the drifted resource is removed from the state to plan the replacement.
+			recorded, diags := recordedFingerprints(ctx, private)
+			if !assert.False(t, diags.HasError(), diags) {
+				return
+			}
+
+			actual, err := driftedFrom(ctx, fingerprintDestination{rows: c.given}, recorded, false)
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
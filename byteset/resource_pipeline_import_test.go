@@ -0,0 +1,70 @@
+package byteset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type privateStateMap map[string]string
+
+func (m privateStateMap) GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics) {
+	return []byte(m[key]), nil
+}
+
+func (m privateStateMap) SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics {
+	m[key] = string(value)
+	return nil
+}
+
+func TestResourcePipeline_Summarize(t *testing.T) {
+	tc := []struct {
+		name     string
+		given    string
+		expected privateStateMap
+	}{
+		{
+			name:  "created and written",
+			given: "CREATE TABLE a (id INT);\nINSERT INTO a VALUES (1);\nINSERT INTO b VALUES (2);\n",
+			expected: privateStateMap{
+				privateKeyTouchedTables: `["a","b"]`,
+				privateKeyCreatedTables: `["a"]`,
+				privateKeyFingerprints:  `{}`,
+			},
+		},
+		{
+			name:  "written only",
+			given: "CREATE TABLE IF NOT EXISTS a (id INT);\nINSERT INTO a VALUES (1);\n",
+			expected: privateStateMap{
+				privateKeyTouchedTables: `["a"]`,
+				privateKeyCreatedTables: `[]`,
+				privateKeyFingerprints:  `{}`,
+			},
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			r := ResourcePipeline{
+				Source: ResourcePipelineSource{
+					Address: types.StringValue("raw://" + c.given),
+					ConnMax: types.Int64Value(1),
+				},
+				Destination: ResourcePipelineDestination{
+					Address: types.StringValue("mysql://root@tcp(127.0.0.1:3306)/byteset"),
+				},
+				DriftDetection: types.StringValue(DriftDetectionNone),
+			}
+
+			actual := privateStateMap{}
+
+			diags := r.Summarize(context.TODO(), actual)
+			if assert.False(t, diags.HasError(), diags) {
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
@@ -2,12 +2,15 @@ package byteset
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
 	"github.com/seal-io/terraform-provider-byteset/utils/strx"
 	"github.com/seal-io/terraform-provider-byteset/utils/testx"
 )
@@ -83,6 +86,52 @@ INSERT INTO company (name, age, address, salary) VALUES ('James', 24, 'Houston',
 					resource.TestCheckResourceAttrSet(resourceName, "cost"),
 				),
 			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     testImportIDOf(strings.ReplaceAll(basicSrc, "\\n", "\n"), basicDst),
+				ImportStateVerify: true,
+				// The outcome of the transfer is not recorded by importing,
+				// as the destination is adopted without piping.
+				ImportStateVerifyIgnore: []string{
+					"destination.conn_max",
+					"cost",
+					"rejected",
+					"stats",
+				},
+				ImportStateCheck: testCheckImported(map[string]string{
+					"source.address":      strings.ReplaceAll(basicSrc, "\\n", "\n"),
+					"destination.address": basicDst,
+					"on_destroy":          OnDestroyNone,
+					"drift_detection":     DriftDetectionNone,
+				}),
+			},
+			{
+				Config: testConfigOfDriftDetection(basicSrc, basicDst, DriftDetectionRowCount),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "drift_detection", DriftDetectionRowCount),
+				),
+			},
+			{
+				// Drift the destination out of band,
+				// the resource is planned to be replaced.
+				PreConfig: func() {
+					_, db, err := sqlx.LoadDatabase(basicDst, 1)
+					if err != nil {
+						t.Fatalf("failed to open MySQL: %v", err)
+					}
+
+					defer func() { _ = db.Close() }()
+
+					_, err = db.Exec("DELETE FROM company WHERE name = 'Kim'")
+					if err != nil {
+						t.Fatalf("failed to drift MySQL: %v", err)
+					}
+				},
+				Config:             testConfigOfDriftDetection(basicSrc, basicDst, DriftDetectionRowCount),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
 		},
 	})
 }
@@ -330,3 +379,59 @@ resource "byteset_pipeline" "test" {
 		"Dst", dst,
 		"DstConnMax", dstConnMax)
 }
+
+func testConfigOfDriftDetection(src, dst, driftDetection string) string {
+	const tmpl = `
+resource "byteset_pipeline" "test" {
+  source = {
+	address = "{{ .Src -}}"
+  }
+  destination = {
+    address = "{{ .Dst }}"
+  }
+  drift_detection = "{{ .DriftDetection }}"
+}`
+
+	return renderConfigTemplate(tmpl,
+		"Src", src,
+		"Dst", dst,
+		"DriftDetection", driftDetection)
+}
+
+func testImportIDOf(src, dst string) string {
+	bs, err := json.Marshal(ResourcePipelineImport{
+		Source:      src,
+		Destination: dst,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return string(bs)
+}
+
+// testCheckImported checks the imported state has the given attributes,
+// and has no outcome of the transfer.
+func testCheckImported(expected map[string]string) resource.ImportStateCheckFunc {
+	return func(is []*terraform.InstanceState) error {
+		if len(is) != 1 {
+			return fmt.Errorf("expected 1 imported state, got %d", len(is))
+		}
+
+		attrs := is[0].Attributes
+
+		for k, v := range expected {
+			if attrs[k] != v {
+				return fmt.Errorf("expected %s to be %q, got %q", k, v, attrs[k])
+			}
+		}
+
+		for _, k := range []string{"cost", "rejected.%", "stats.%"} {
+			if v, ok := attrs[k]; ok && v != "" && v != "0" {
+				return fmt.Errorf("expected %s to be unset, got %q", k, v)
+			}
+		}
+
+		return nil
+	}
+}
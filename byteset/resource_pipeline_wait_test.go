@@ -0,0 +1,90 @@
+package byteset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+func TestWaitOf(t *testing.T) {
+	type input struct {
+		timeout  types.String
+		interval types.String
+		query    types.String
+	}
+
+	tc := []struct {
+		name     string
+		given    input
+		expected sqlx.Wait
+		wantErr  bool
+	}{
+		{
+			name: "default",
+			given: input{
+				timeout:  types.StringNull(),
+				interval: types.StringNull(),
+				query:    types.StringNull(),
+			},
+			expected: sqlx.Wait{},
+		},
+		{
+			name: "specified",
+			given: input{
+				timeout:  types.StringValue("2m"),
+				interval: types.StringValue("1.5s"),
+				query:    types.StringValue("SELECT 1"),
+			},
+			expected: sqlx.Wait{
+				Timeout:  2 * time.Minute,
+				Interval: 1500 * time.Millisecond,
+				Query:    "SELECT 1",
+			},
+		},
+		{
+			name: "zero timeout",
+			given: input{
+				timeout:  types.StringValue("0s"),
+				interval: types.StringNull(),
+				query:    types.StringNull(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative interval",
+			given: input{
+				timeout:  types.StringNull(),
+				interval: types.StringValue("-1s"),
+				query:    types.StringNull(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid interval",
+			given: input{
+				timeout:  types.StringNull(),
+				interval: types.StringValue("often"),
+				query:    types.StringNull(),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := waitOf(c.given.timeout, c.given.interval, c.given.query)
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+data "byteset_sql_file" "example" {
+  address = "file:///path/to/seed.sql"
+  dialect = "postgres"
+}
+
+output "unclassified" {
+  value = data.byteset_sql_file.example.unclassified
+}
+
+output "insert_rows" {
+  value = data.byteset_sql_file.example.insert_rows
+}
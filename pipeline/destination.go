@@ -6,7 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -15,39 +15,197 @@ import (
 	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
 )
 
+// Receiver receives the sql piped from Source.
+type Receiver interface {
+	// Exec executes the given sql.
+	Exec(ctx context.Context, sql string) error
+
+	// Complete flushes all caching sql and marks the piping as completed.
+	Complete(ctx context.Context) error
+}
+
 type Destination interface {
 	io.Closer
+	Receiver
 
 	// Flush executes all caching sql.
 	Flush(ctx context.Context) error
 
-	// Exec executes the given sql.
-	Exec(ctx context.Context, sql string) error
+	// Touched returns the tables created or written by the executed sql,
+	// in order of their first appearance.
+	Touched() []string
+
+	// Created returns the tables created by the executed sql,
+	// in order of their first appearance, which are part of Touched.
+	Created() []string
+
+	// Truncate removes all rows of the given tables.
+	Truncate(ctx context.Context, tables []string) error
+
+	// Drop removes the given tables.
+	Drop(ctx context.Context, tables []string) error
+
+	// Fingerprint returns the fingerprint of each given table,
+	// only counts the rows if checksum is false.
+	Fingerprint(ctx context.Context, tables []string, checksum bool) (map[string]string, error)
+
+	// Rejection returns the rejected statements if continuing on error.
+	Rejection() Rejection
+
+	// Stats returns the statistics of the execution.
+	Stats() Stats
 }
 
-func NewDestination(ctx context.Context, addr string, addrConnMax, bufSegCap int) (Destination, error) {
+type DestinationOptions struct {
+	// ConnMax specifies the maximum opening connections of the database.
+	ConnMax int
+	// BatchCap specifies the maximum value statement number for once insert statement.
+	BatchCap int
+	// Checkpoint specifies the digest to record the progress with,
+	// resumes from the recorded progress if found,
+	// disables recording if blank.
+	Checkpoint string
+	// Retry specifies how to retry the transient errors,
+	// like deadlock or dropped connection,
+	// the statements executed in the sentry session are never retried.
+	Retry sqlx.Retry
+	// Bulk specifies to load the literal inserting rows with the fastest ingestion protocol,
+	// i.e. COPY FROM STDIN for Postgres, LOAD DATA LOCAL INFILE for MySQL and bulk copy for SQLServer,
+	// the rows are inserted if the database does not support.
+	Bulk bool
+	// Parallel specifies to stage the inserting statements by table until the next non-inserting statement,
+	// and then load the independent tables concurrently,
+	// the dependencies are detected by the foreign keys of the destination,
+	// ignored if Checkpoint is not blank.
+	Parallel bool
+	// DependsOn declares the extra dependencies of tables for Parallel,
+	// the key is the table name, and the value is the table names it depends on.
+	DependsOn map[string][]string
+	// FastLoad specifies to disable the constraints and triggers during loading,
+	// i.e. FOREIGN_KEY_CHECKS and UNIQUE_CHECKS of each MySQL session,
+	// session_replication_role of each Postgres session,
+	// the constraints and triggers of each written table on SQLServer and Oracle,
+	// the integrity is validated at completion,
+	// Atomic and writing a new table inside the transaction of source are refused on Oracle.
+	FastLoad bool
+	// Atomic specifies to execute the whole pipeline in one transaction of one session,
+	// which is rolled back if not completed,
+	// the transaction control statements of the source are ignored,
+	// Checkpoint, Parallel and Bulk are ignored if true.
+	Atomic bool
+	// OnError specifies how to handle the failed statement, abort or continue,
+	// the failed statement is rejected if continue.
+	OnError string
+	// MaxErrors specifies the maximum rejected statements if OnError is continue,
+	// 0 means no limit.
+	MaxErrors int
+	// RejectPath specifies the local file to write the rejected statements in,
+	// disables writing if blank.
+	RejectPath string
+	// Wait specifies how to wait for the database to be ready.
+	Wait sqlx.Wait
+}
+
+func NewDestination(ctx context.Context, addr string, opts DestinationOptions) (Destination, error) {
+	ctx = sqlx.MaskSecrets(ctx, addr)
+
+	var inits []string
+
+	if opts.FastLoad {
+		drv, _, err := sqlx.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load database from %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+		}
+
+		// Disabling the constraints is DDL on Oracle,
+		// which commits the transaction implicitly.
+		if drv == sqlx.OracleDialect && opts.Atomic {
+			return nil, errors.New("cannot fast load with atomic on Oracle, " +
+				"which commits the transaction implicitly at disabling the constraints")
+		}
+
+		inits = fastLoadInits(drv)
+	}
+
 	// Load database.
-	drv, db, err := sqlx.LoadDatabase(addr, addrConnMax)
+	drv, db, err := sqlx.LoadDatabase(addr, opts.ConnMax, inits...)
 	if err != nil {
-		return nil, fmt.Errorf("cannot load database from %q: %w", addr, err)
+		return nil, fmt.Errorf("cannot load database from %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
 	}
 
 	// Detect connectivity.
-	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
-	defer cancel()
-
-	err = sqlx.IsDatabaseConnected(ctx, db)
+	err = sqlx.IsDatabaseConnected(ctx, db, opts.Wait)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect database on %q: %w", addr, err)
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot connect database on %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
 	}
 
-	return &dst{
+	d := &dst{
 		drv:       drv,
 		db:        db,
 		dbConnMax: db.Stats().MaxOpenConnections,
-		buf:       map[string][][]string{},
-		bufSegCap: bufSegCap,
-	}, nil
+		buf:       map[string][]batch{},
+		bufSegCap: opts.BatchCap,
+		bufTables: map[string]string{},
+		bulkBuf:   map[string]*bulkRows{},
+		ckpt:      opts.Checkpoint,
+		retry:     opts.Retry,
+		parallel:  opts.Parallel,
+		fastLoad:  opts.FastLoad,
+		relaxed:   map[string][]string{},
+		touched:   map[string]struct{}{},
+		created:   map[string]struct{}{},
+		stats:     newStats(),
+	}
+
+	d.retry.Retried = &d.stats.retries
+
+	if len(opts.DependsOn) != 0 {
+		d.dependsOn = make(map[string][]string, len(opts.DependsOn))
+
+		for t, ds := range opts.DependsOn {
+			t = normalizeTable(t)
+			for i := range ds {
+				d.dependsOn[t] = append(d.dependsOn[t], normalizeTable(ds[i]))
+			}
+		}
+	}
+
+	d.bulk.Store(opts.Bulk)
+
+	if opts.OnError == OnErrorContinue {
+		d.rej, err = newRejector(opts.MaxErrors, opts.RejectPath)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+
+	// Start the transaction wrapping the whole pipeline.
+	if opts.Atomic {
+		d.ckpt = ""
+		d.atomic = true
+
+		err = d.beginAtomic(ctx)
+		if err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("cannot begin transaction on %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+		}
+	}
+
+	// Load checkpoint.
+	if d.ckpt != "" {
+		d.ckptOffset, err = d.loadCheckpoint(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load checkpoint on %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+		}
+
+		if d.ckptOffset > 0 {
+			tflog.Info(ctx, "Resuming from checkpoint", map[string]any{"offset": d.ckptOffset})
+		}
+	}
+
+	return d, nil
 }
 
 type dst struct {
@@ -55,14 +213,52 @@ type dst struct {
 	db        *stdsql.DB
 	dbConnMax int
 
-	buf       map[string][][]string
+	buf       map[string][]batch
 	bufSegCap int
+	bufTables map[string]string
+	bufOrder  []string
+	bufRows   int
+
+	bulk        atomic.Bool
+	bulkBuf     map[string]*bulkRows
+	bulkBufRows int
+
+	fks       map[string]map[string]struct{}
+	fksLoaded bool
+
+	parallel  bool
+	dependsOn map[string][]string
+	stg       *staging
+
+	fastLoad     bool
+	relaxed      map[string][]string
+	relaxedOrder []string
 
 	sentry    *stdsql.Conn
 	sentryUse int
+	sentryTx  bool
+	atomic    bool
+
+	ckpt       string
+	ckptOffset int
+	offset     int
+	bufOffset  int
+
+	retry sqlx.Retry
+	rej   *rejector
+	stats *stats
+
+	touched      map[string]struct{}
+	touchedOrder []string
+	created      map[string]struct{}
 }
 
 func (in *dst) Close() error {
+	in.closeStaging()
+	in.rollbackAtomic()
+	in.unrelax()
+	_ = in.rej.Close()
+
 	if in.sentry != nil {
 		_ = in.sentry.Close()
 	}
@@ -71,76 +267,204 @@ func (in *dst) Close() error {
 }
 
 func (in *dst) Flush(ctx context.Context) error {
-	if len(in.buf) == 0 {
+	if err := in.flushStaged(ctx); err != nil {
+		return err
+	}
+
+	if len(in.buf) == 0 && len(in.bulkBuf) == 0 {
 		return nil
 	}
 
-	// Construct DML(insert).
-	sqls := make([]string, 0,
-		func() (s int) {
-			for i := range in.buf {
-				s += len(in.buf[i])
-			}
+	// Construct DML(insert) in order of the first appearance.
+	units := make([]flushUnit, 0, 2*len(in.bufOrder))
 
-			return
-		}())
+	for _, p := range in.bufOrder {
+		if b, exist := in.bulkBuf[p]; exist {
+			units = append(units, flushUnit{table: in.bufTables[p], bulk: b})
+		}
 
-	for p := range in.buf {
-		for i := 0; i < len(in.buf[p]); i++ {
-			sqls = append(sqls,
-				p+"VALUES "+strings.Join(in.buf[p][i], ", "))
+		if len(in.buf[p]) != 0 {
+			units = append(units, flushUnit{table: in.bufTables[p], batches: in.buf[p]})
 		}
 	}
 
-	in.buf = map[string][][]string{}
+	in.buf = map[string][]batch{}
+	in.bulkBuf = map[string]*bulkRows{}
+	in.bufRows = 0
+	in.bulkBufRows = 0
+	in.bufOrder = in.bufOrder[:0]
 
-	// Execute DML(insert) in single session.
+	// Execute DML(insert) in single session,
+	// bisect with savepoints only if inside a transaction.
 	if in.sentry != nil {
-		for i := 0; i < len(sqls); i++ {
-			err := sqlx.Exec(ctx, in.sentry, sqls[i])
-			if err != nil {
-				return err
+		for _, u := range units {
+			for i := range u.batches {
+				if err := in.execBatch(ctx, in.sentry, u.batches[i], in.sentryTx); err != nil {
+					return err
+				}
 			}
 		}
 
 		return nil
 	}
 
-	// Or execute DML(insert) in multiple sessions.
-	gp := pool.New().
-		WithMaxGoroutines(in.dbConnMax).
-		WithContext(ctx).
-		WithFirstError()
+	// Or execute DML(insert) with checkpoint in single transaction.
+	if in.ckpt != "" {
+		err := in.retry.Do(ctx, func(ctx context.Context) error {
+			return in.flushWithCheckpoint(ctx, units)
+		})
+		if err == nil || in.rej == nil || ctx.Err() != nil || errors.Is(err, errTooManyErrors) {
+			return err
+		}
 
-	for i := 0; i < len(sqls); i++ {
-		sql := sqls[i]
+		// Insert the bulk rows instead as the transaction is rolled back,
+		// the inserting statements are bisected to reject the failed tuples only,
+		// the checkpoint is not advanced if rolled back again.
+		inserts, ok := withoutBulk(units, in.bufSegCap)
+		if !ok {
+			return err
+		}
+
+		tflog.Warn(ctx, "Cannot flush with checkpoint, inserting instead", map[string]any{
+			"error": err.Error(),
+		})
 
-		gp.Go(func(ctx context.Context) error {
-			return sqlx.Exec(ctx, in.db, sql)
+		return in.retry.Do(ctx, func(ctx context.Context) error {
+			return in.flushWithCheckpoint(ctx, inserts)
 		})
 	}
 
-	return gp.Wait()
+	// Or execute DML(insert) in multiple sessions,
+	// level by level, the tables of the same level do not depend on each other.
+	for _, lvl := range in.levels(ctx, units) {
+		gp := pool.New().
+			WithMaxGoroutines(in.dbConnMax).
+			WithContext(ctx).
+			WithFirstError()
+
+		for _, u := range lvl {
+			if u.bulk != nil {
+				b := u.bulk
+
+				gp.Go(func(ctx context.Context) error {
+					return in.flushBulk(ctx, b)
+				})
+			}
+
+			for i := range u.batches {
+				b := u.batches[i]
+
+				gp.Go(func(ctx context.Context) error {
+					return in.execBatch(ctx, in.db, b, false)
+				})
+			}
+		}
+
+		if err := gp.Wait(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (in *dst) Complete(ctx context.Context) error {
+	if err := in.Flush(ctx); err != nil {
+		return err
+	}
+
+	if err := in.restore(ctx); err != nil {
+		return err
+	}
+
+	if err := in.commitAtomic(ctx); err != nil {
+		return err
+	}
+
+	if in.ckpt != "" {
+		return in.clearCheckpoint(ctx)
+	}
+
+	return nil
+}
+
+// execVerbatim executes the given statement which cannot be classified,
+// in the sentry session if found, or in one session without retrying.
+func (in *dst) execVerbatim(ctx context.Context, sql string) error {
+	in.stats.statement(StatementUnknown)
+
+	if err := in.Flush(ctx); err != nil {
+		return err
+	}
+
+	if in.sentry != nil {
+		return sqlx.Exec(ctx, in.sentry, sql)
+	}
+
+	return sqlx.Exec(ctx, in.db, sql)
 }
 
 func (in *dst) Exec(ctx context.Context, sql string) error {
+	in.offset += 1
+
 	sqlp := sqlx.Parse(in.drv, sql)
 
-	if sqlp.Unknown() {
-		tflog.Trace(ctx, "Ignored", map[string]any{"sql": sql})
+	// Skip if applied.
+	if in.offset <= in.ckptOffset {
+		tflog.Trace(ctx, "Skipped", map[string]any{"sql": sqlx.RedactSQL(ctx, sql), "offset": in.offset})
+		in.touch(sqlp)
+		in.stats.skip()
+
+		return nil
+	}
+
+	if sqlp.Unknown() && !isVerbatim(ctx) {
+		tflog.Trace(ctx, "Ignored", map[string]any{"sql": sqlx.RedactSQL(ctx, sql)})
+		in.stats.ignore(sql)
+
 		return nil
 	}
 
 	err := in.exec(ctx, sqlp, sql)
-	if err != nil {
+	if err = in.tolerate(ctx, LineFrom(ctx), sql, err); err != nil {
+		// The failed value tuple is found by flushing.
+		var te *TupleError
+		if errors.As(err, &te) {
+			return err
+		}
+
 		return fmt.Errorf("failed to execute sql %q: %w", sql, err)
 	}
 
+	// Record checkpoint if not buffered.
+	if in.bufOffset != in.offset {
+		return in.checkpoint(ctx)
+	}
+
 	return nil
 }
 
 func (in *dst) exec(ctx context.Context, sqlp sqlx.Parsed, sql string) error {
+	if sqlp.Unknown() {
+		return in.execVerbatim(ctx, sql)
+	}
+
 	if typ, ok := sqlp.TCL(); ok {
+		// Ignore TCL inside the atomic transaction.
+		if in.atomic {
+			tflog.Trace(ctx, "Ignored", map[string]any{"sql": sqlx.RedactSQL(ctx, sql)})
+			in.stats.ignore(sql)
+
+			return nil
+		}
+
+		in.stats.statement(StatementTCL)
+
+		// Flush.
+		if err := in.Flush(ctx); err != nil {
+			return err
+		}
+
 		// Prepare sentry.
 		if in.sentry == nil {
 			conn, err := in.db.Conn(ctx)
@@ -150,11 +474,6 @@ func (in *dst) exec(ctx context.Context, sqlp sqlx.Parsed, sql string) error {
 			in.sentry = conn
 		}
 
-		// Flush.
-		if err := in.Flush(ctx); err != nil {
-			return err
-		}
-
 		// Execute TCL in sentry session.
 		if err := sqlx.Exec(ctx, in.sentry, sql); err != nil {
 			return err
@@ -166,6 +485,15 @@ func (in *dst) exec(ctx context.Context, sqlp sqlx.Parsed, sql string) error {
 			in.sentryUse -= 1
 		}
 
+		// Record whether the sentry session is inside a transaction,
+		// the sentry session opened by LOCK TABLES is not.
+		switch {
+		case sqlx.IsBegin(sql):
+			in.sentryTx = true
+		case sqlx.IsEnd(sql):
+			in.sentryTx = false
+		}
+
 		// Release sentry.
 		if in.sentryUse <= 0 {
 			err := in.sentry.Close()
@@ -173,43 +501,111 @@ func (in *dst) exec(ctx context.Context, sqlp sqlx.Parsed, sql string) error {
 				return err
 			}
 			in.sentry = nil
+			in.sentryTx = false
 		}
 
 		return nil
 	}
 
 	if sqlp.DCL() || sqlp.DDL() {
+		if sqlp.DDL() {
+			in.stats.statement(StatementDDL)
+		} else {
+			in.stats.statement(StatementDCL)
+		}
+
 		// Flush.
 		if err := in.Flush(ctx); err != nil {
 			return err
 		}
 
-		var ex sqlx.Executor = in.db
+		in.warnAtomicDDL(ctx, sql)
+
+		// Execute DDL/DCL in sentry session if found,
+		// or execute DDL/DCL in one session.
+		var err error
 		if in.sentry != nil {
-			ex = in.sentry
+			err = sqlx.Exec(ctx, in.sentry, sql)
+		} else {
+			err = in.retry.Exec(ctx, in.db, sql)
+		}
+
+		if err != nil {
+			return err
 		}
 
-		// Execute DDL/DCL in one session.
-		return sqlx.Exec(ctx, ex, sql)
+		in.touch(sqlp)
+
+		// Reload the foreign keys at next flushing.
+		in.fksLoaded = false
+
+		return nil
 	}
 
 	if typ, ok := sqlp.DML(); ok {
-		inst, ok := sqlp.AsDMLInsert()
+		in.stats.statement(StatementDML)
+
+		if err := in.relax(ctx, sqlp); err != nil {
+			return err
+		}
+
+		if in.stageable() {
+			staged, err := in.stageInsert(ctx, sqlp)
+			if err != nil || staged {
+				return err
+			}
+		}
+
+		var inst sqlx.DMLInsert
+
+		if in.bulkable() {
+			var bi sqlx.DMLBulkInsert
+
+			bi, ok = sqlp.AsDMLBulkInsert()
+			if ok && bi.Rows != nil &&
+				(in.drv != sqlx.SQLServerDialect || len(bi.Columns) != 0) {
+				tbl, exist := in.bufTables[bi.Prefix]
+				if !exist {
+					tbl, _ = sqlp.Table()
+					in.bufTables[bi.Prefix] = tbl
+				}
+
+				return in.bufferBulk(ctx, tbl, bi)
+			}
+			inst = bi.DMLInsert
+		} else {
+			inst, ok = sqlp.AsDMLInsert()
+		}
+
 		if ok {
+			// Record table with prefix,
+			// avoid parsing the table name for each insert statement.
+			tbl, exist := in.bufTables[inst.Prefix]
+			if !exist {
+				tbl, _ = sqlp.Table()
+				in.bufTables[inst.Prefix] = tbl
+			}
+			in.touchTable(tbl)
+
+			in.orderPrefix(inst.Prefix)
+
 			// Prepare first buffer segment.
 			if len(in.buf[inst.Prefix]) == 0 {
-				in.buf[inst.Prefix] = append(in.buf[inst.Prefix], nil)
+				in.buf[inst.Prefix] = append(in.buf[inst.Prefix], batch{prefix: inst.Prefix})
 			}
 
 			lsi := len(in.buf[inst.Prefix]) - 1
 
 			// Append latest buffer segment.
-			in.buf[inst.Prefix][lsi] = append(in.buf[inst.Prefix][lsi], inst.Values...)
+			in.buf[inst.Prefix][lsi].add(inst.Values, LineFrom(ctx))
+			in.bufOffset = in.offset
+			in.bufRows += len(inst.Values)
+			in.stats.buffer(in.bufRows + in.bulkBufRows)
 
 			// Increase segment of buffer.
-			if len(in.buf[inst.Prefix][lsi]) >= in.bufSegCap {
+			if len(in.buf[inst.Prefix][lsi].values) >= in.bufSegCap {
 				if len(in.buf[inst.Prefix]) < in.dbConnMax {
-					in.buf[inst.Prefix] = append(in.buf[inst.Prefix], nil)
+					in.buf[inst.Prefix] = append(in.buf[inst.Prefix], batch{prefix: inst.Prefix})
 					lsi += 1
 				}
 			}
@@ -217,7 +613,7 @@ func (in *dst) exec(ctx context.Context, sqlp sqlx.Parsed, sql string) error {
 			// Flush if reaches limitations.
 			isBufFull := in.dbConnMax != 1 && len(in.buf) >= in.dbConnMax
 			isBufSegFull := (in.sentry != nil || lsi >= in.dbConnMax) &&
-				len(in.buf[inst.Prefix][lsi]) >= in.bufSegCap
+				len(in.buf[inst.Prefix][lsi].values) >= in.bufSegCap
 
 			if isBufFull || isBufSegFull {
 				return in.Flush(ctx)
@@ -231,21 +627,29 @@ func (in *dst) exec(ctx context.Context, sqlp sqlx.Parsed, sql string) error {
 			return err
 		}
 
+		in.touch(sqlp)
+
 		// Execute DML in sentry session if found.
 		if in.sentry != nil {
-			return sqlx.Exec(ctx, in.sentry, sql)
+			return in.countInsert(ctx, sqlp, func(ctx context.Context) (int64, error) {
+				return sqlx.ExecAffected(ctx, in.sentry, sql)
+			})
 		}
 
 		// Execute DML in single session.
 		if typ == sqlx.SingleSessionDML {
-			return sqlx.Exec(ctx, in.db, sql)
+			return in.countInsert(ctx, sqlp, func(ctx context.Context) (int64, error) {
+				return in.retry.ExecAffected(ctx, in.db, sql)
+			})
 		}
 
 		// Or execute DML in multiple sessions.
 		cs := make([]*stdsql.Conn, 0, in.dbConnMax)
 		defer func() {
 			for i := range cs {
-				_ = cs[i].Close()
+				if cs[i] != nil {
+					_ = cs[i].Close()
+				}
 			}
 		}()
 
@@ -264,10 +668,28 @@ func (in *dst) exec(ctx context.Context, sqlp sqlx.Parsed, sql string) error {
 			WithFirstError()
 
 		for i := 0; i < in.dbConnMax; i++ {
-			c := cs[i]
+			i := i
 
 			gp.Go(func(ctx context.Context) error {
-				return sqlx.Exec(ctx, c, sql)
+				return in.retry.DoIdempotent(ctx, func(ctx context.Context) error {
+					// Take a fresh session at retrying, as the failed one may be broken,
+					// the other sessions are held, so that the fresh one is different from them.
+					if cs[i] == nil {
+						c, err := in.db.Conn(ctx)
+						if err != nil {
+							return err
+						}
+						cs[i] = c
+					}
+
+					err := sqlx.Exec(ctx, cs[i], sql)
+					if err != nil {
+						_ = cs[i].Close()
+						cs[i] = nil
+					}
+
+					return err
+				})
 			})
 		}
 
@@ -276,3 +698,72 @@ func (in *dst) exec(ctx context.Context, sqlp sqlx.Parsed, sql string) error {
 
 	return errors.New("nothing to do")
 }
+
+// countInsert executes the given DML with the given exec,
+// and counts the affected rows into the inserted rows if the DML is an insert statement.
+func (in *dst) countInsert(
+	ctx context.Context,
+	sqlp sqlx.Parsed,
+	exec func(ctx context.Context) (int64, error),
+) error {
+	start := time.Now()
+
+	n, err := exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if n <= 0 || !sqlx.IsInsert(sqlp.Origin()) {
+		return nil
+	}
+
+	if tbl, ok := sqlp.Table(); ok {
+		in.stats.insert(tbl, int(n))
+		in.stats.elapse(tbl, start)
+	}
+
+	return nil
+}
+
+func (in *dst) Touched() []string {
+	return in.touchedOrder
+}
+
+func (in *dst) Created() []string {
+	var r []string
+
+	for _, t := range in.touchedOrder {
+		if _, ok := in.created[t]; ok {
+			r = append(r, t)
+		}
+	}
+
+	return r
+}
+
+// touch records the table which the given sql creates or writes to.
+func (in *dst) touch(sqlp sqlx.Parsed) {
+	tbl, ok := sqlp.Table()
+	if !ok {
+		return
+	}
+
+	in.touchTable(tbl)
+
+	if tbl != "" && sqlp.DDL() && sqlx.IsCreateTable(sqlp.Origin()) {
+		in.created[tbl] = struct{}{}
+	}
+}
+
+func (in *dst) touchTable(tbl string) {
+	if tbl == "" {
+		return
+	}
+
+	if _, exist := in.touched[tbl]; exist {
+		return
+	}
+
+	in.touched[tbl] = struct{}{}
+	in.touchedOrder = append(in.touchedOrder, tbl)
+}
@@ -0,0 +1,95 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// beginAtomic starts the transaction wrapping the whole pipeline in sentry session.
+func (in *dst) beginAtomic(ctx context.Context) error {
+	var begin string
+
+	switch in.drv {
+	case sqlx.MySQLDialect:
+		begin = "START TRANSACTION"
+	case sqlx.PostgresDialect:
+		begin = "BEGIN"
+	case sqlx.SQLServerDialect:
+		begin = "BEGIN TRANSACTION"
+	default:
+		return fmt.Errorf("cannot execute atomically on %s, which commits each statement", in.drv)
+	}
+
+	conn, err := in.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = sqlx.Exec(ctx, conn, begin); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	in.sentry = conn
+	in.sentryUse = 1
+	in.sentryTx = true
+
+	return nil
+}
+
+// commitAtomic commits the transaction wrapping the whole pipeline.
+func (in *dst) commitAtomic(ctx context.Context) error {
+	if !in.atomic || in.sentry == nil {
+		return nil
+	}
+
+	if err := sqlx.Exec(ctx, in.sentry, "COMMIT"); err != nil {
+		return fmt.Errorf("cannot commit: %w", err)
+	}
+
+	in.atomic = false
+
+	return nil
+}
+
+// rollbackAtomic rolls back the transaction wrapping the whole pipeline if not committed,
+// which is used to recover from failure or cancellation.
+func (in *dst) rollbackAtomic() {
+	if !in.atomic || in.sentry == nil {
+		return
+	}
+
+	in.atomic = false
+
+	// The given context may be canceled already.
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	if err := sqlx.Exec(ctx, in.sentry, "ROLLBACK"); err != nil {
+		tflog.Warn(ctx, "Cannot rollback", map[string]any{"error": err.Error()})
+		return
+	}
+
+	tflog.Info(ctx, "Rolled back")
+
+	// The disabling constraints and triggers are rolled back as well.
+	if in.drv == sqlx.SQLServerDialect {
+		in.relaxed = map[string][]string{}
+		in.relaxedOrder = nil
+	}
+}
+
+// warnAtomicDDL warns the DDL commits the transaction implicitly on MySQL.
+func (in *dst) warnAtomicDDL(ctx context.Context, sql string) {
+	if !in.atomic || in.drv != sqlx.MySQLDialect {
+		return
+	}
+
+	tflog.Warn(ctx, "DDL commits the atomic transaction implicitly on MySQL",
+		map[string]any{"sql": sqlx.RedactSQL(ctx, sql)})
+}
@@ -0,0 +1,168 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// batch holds the value tuples of an insert statement with their source line numbers.
+type batch struct {
+	prefix string
+	values []string
+	lines  []int
+}
+
+// add appends the given value tuples of the given source line.
+func (b *batch) add(values []string, line int) {
+	b.values = append(b.values, values...)
+	for range values {
+		b.lines = append(b.lines, line)
+	}
+}
+
+func (b batch) sql() string {
+	return b.prefix + "VALUES " + strings.Join(b.values, ", ")
+}
+
+// slice returns the batch of the value tuples in range [i, j).
+func (b batch) slice(i, j int) batch {
+	return batch{
+		prefix: b.prefix,
+		values: b.values[i:j],
+		lines:  b.lines[i:j],
+	}
+}
+
+// split returns the batches with at most the given number of value tuples.
+func (b batch) split(n int) []batch {
+	bs := make([]batch, 0, (len(b.values)+n-1)/n)
+
+	for i := 0; i < len(b.values); i += n {
+		j := i + n
+		if j > len(b.values) {
+			j = len(b.values)
+		}
+
+		bs = append(bs, b.slice(i, j))
+	}
+
+	return bs
+}
+
+// TupleError describes the value tuple failed in inserting.
+type TupleError struct {
+	// Line is the source line number of the tuple, 0 if unknown.
+	Line int
+	// Tuple is the value tuple.
+	Tuple string
+	// Err is the reason.
+	Err error
+}
+
+func (e *TupleError) Error() string {
+	return fmt.Sprintf("cannot insert %s at line %d: %v", e.Tuple, e.Line, e.Err)
+}
+
+func (e *TupleError) Unwrap() error {
+	return e.Err
+}
+
+// execBatch executes the given batch,
+// re-executes in halves if failed to find out the failed value tuple,
+// which is rejected if continuing on error,
+// the given tx indicates the executor is in a transaction and executes with savepoint.
+func (in *dst) execBatch(ctx context.Context, ex sqlx.Executor, b batch, tx bool) error {
+	if len(b.values) == 0 {
+		return nil
+	}
+
+	in.stats.batch()
+	defer in.stats.elapse(in.bufTables[b.prefix], time.Now())
+
+	return in.bisect(ctx, ex, b, tx)
+}
+
+func (in *dst) bisect(ctx context.Context, ex sqlx.Executor, b batch, tx bool) error {
+	if len(b.values) == 0 {
+		return nil
+	}
+
+	err := in.execSavepoint(ctx, ex, b.sql(), tx)
+	if err == nil {
+		in.stats.insert(in.bufTables[b.prefix], len(b.values))
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return err
+	}
+
+	if len(b.values) == 1 {
+		return in.skip(ctx, b.lines[0], b.sql(), &TupleError{
+			Line:  b.lines[0],
+			Tuple: b.values[0],
+			Err:   err,
+		})
+	}
+
+	m := len(b.values) / 2
+
+	if err = in.bisect(ctx, ex, b.slice(0, m), tx); err != nil {
+		return err
+	}
+
+	return in.bisect(ctx, ex, b.slice(m, len(b.values)), tx)
+}
+
+// execSavepoint executes the given sql,
+// rollbacks to the savepoint if failed in transaction.
+func (in *dst) execSavepoint(ctx context.Context, ex sqlx.Executor, sql string, tx bool) error {
+	if !tx {
+		// Never retry in the sentry session.
+		if in.sentry != nil {
+			return sqlx.Exec(ctx, ex, sql)
+		}
+
+		return in.retry.Exec(ctx, ex, sql)
+	}
+
+	save, rollback, release := savepointStatements(in.drv)
+
+	if err := sqlx.Exec(ctx, ex, save); err != nil {
+		return err
+	}
+
+	err := sqlx.Exec(ctx, ex, sql)
+	if err != nil {
+		if rerr := sqlx.Exec(ctx, ex, rollback); rerr != nil {
+			return fmt.Errorf("%w, and cannot rollback to savepoint: %v", err, rerr)
+		}
+
+		return err
+	}
+
+	if release != "" {
+		return sqlx.Exec(ctx, ex, release)
+	}
+
+	return nil
+}
+
+// savepointStatements returns the statements to save, rollback to and release the savepoint,
+// release is blank if not supported.
+func savepointStatements(drv string) (save, rollback, release string) {
+	const name = "byteset_bisect"
+
+	switch drv {
+	case sqlx.SQLServerDialect:
+		return "SAVE TRANSACTION " + name, "ROLLBACK TRANSACTION " + name, ""
+	case sqlx.OracleDialect:
+		return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, ""
+	}
+
+	return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, "RELEASE SAVEPOINT " + name
+}
@@ -0,0 +1,174 @@
+package pipeline
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+	"github.com/seal-io/terraform-provider-byteset/utils/testx"
+)
+
+// fakeExecutor records the executed sql,
+// and fails the sql contains the bad value tuple.
+type fakeExecutor struct {
+	bad  string
+	sqls []string
+}
+
+func (e *fakeExecutor) ExecContext(_ context.Context, sql string, _ ...any) (stdsql.Result, error) {
+	e.sqls = append(e.sqls, sql)
+
+	if strings.Contains(sql, e.bad) {
+		return nil, errors.New("constraint violated")
+	}
+
+	return nil, nil
+}
+
+func TestDestination_execBatch(t *testing.T) {
+	var b batch
+	b.prefix = "INSERT INTO t "
+	b.add([]string{"(1)", "(2)"}, 3)
+	b.add([]string{"(3)", "(4)", "(5)"}, 4)
+
+	t.Run("abort", func(t *testing.T) {
+		d := &dst{drv: sqlx.MySQLDialect, stats: newStats()}
+		ex := &fakeExecutor{bad: "(4)"}
+
+		err := d.execBatch(context.TODO(), ex, b, false)
+
+		var te *TupleError
+		if assert.ErrorAs(t, err, &te) {
+			assert.Equal(t, 4, te.Line)
+			assert.Equal(t, "(4)", te.Tuple)
+		}
+
+		assert.Equal(t, []string{
+			"INSERT INTO t VALUES (1), (2), (3), (4), (5)",
+			"INSERT INTO t VALUES (1), (2)",
+			"INSERT INTO t VALUES (3), (4), (5)",
+			"INSERT INTO t VALUES (3)",
+			"INSERT INTO t VALUES (4), (5)",
+			"INSERT INTO t VALUES (4)",
+		}, ex.sqls)
+	})
+
+	t.Run("continue in transaction", func(t *testing.T) {
+		r, err := newRejector(0, "")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		d := &dst{
+			drv:       sqlx.PostgresDialect,
+			bufTables: map[string]string{"INSERT INTO t ": "t"},
+			rej:       r,
+			stats:     newStats(),
+		}
+		ex := &fakeExecutor{bad: "(2)"}
+
+		err = d.execBatch(context.TODO(), ex, b.slice(0, 3), true)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"SAVEPOINT byteset_bisect",
+			"INSERT INTO t VALUES (1), (2), (3)",
+			"ROLLBACK TO SAVEPOINT byteset_bisect",
+			"SAVEPOINT byteset_bisect",
+			"INSERT INTO t VALUES (1)",
+			"RELEASE SAVEPOINT byteset_bisect",
+			"SAVEPOINT byteset_bisect",
+			"INSERT INTO t VALUES (2), (3)",
+			"ROLLBACK TO SAVEPOINT byteset_bisect",
+			"SAVEPOINT byteset_bisect",
+			"INSERT INTO t VALUES (2)",
+			"ROLLBACK TO SAVEPOINT byteset_bisect",
+			"SAVEPOINT byteset_bisect",
+			"INSERT INTO t VALUES (3)",
+			"RELEASE SAVEPOINT byteset_bisect",
+		}, ex.sqls)
+
+		assert.Equal(t, Rejection{
+			Count: 1,
+			Rejects: []Reject{
+				{Line: 3, SQL: "INSERT INTO t VALUES (2)", Error: "constraint violated"},
+			},
+		}, d.Rejection())
+
+		st := d.Stats()
+		assert.Equal(t, 1, st.Batches)
+		assert.Equal(t, map[string]int{"t": 2}, st.InsertRows)
+	})
+}
+
+func TestBatch_split(t *testing.T) {
+	var b batch
+	b.prefix = "INSERT INTO t "
+	b.add([]string{"(1)", "(2)", "(3)"}, 7)
+
+	actual := b.split(2)
+	expected := []batch{
+		{prefix: "INSERT INTO t ", values: []string{"(1)", "(2)"}, lines: []int{7, 7}},
+		{prefix: "INSERT INTO t ", values: []string{"(3)"}, lines: []int{7}},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestDestination_bisectLockTables(t *testing.T) {
+	f, err := testx.File("testdata/mysql-lock.sql")
+	if err != nil {
+		panic(err)
+	}
+
+	src := &srcFile{f: f}
+	defer func() { _ = src.Close() }()
+
+	db := &fakeDB{
+		fail: func(sql string) error {
+			if strings.Contains(sql, "'Qandahar'") {
+				return errors.New("constraint violated")
+			}
+
+			return nil
+		},
+	}
+
+	d := newFakeDestination(sqlx.MySQLDialect, db)
+	defer func() { _ = d.Close() }()
+
+	d.rej, err = newRejector(0, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	err = src.Pipe(context.TODO(), d)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The sentry session opened by LOCK TABLES is not a transaction,
+	// so that bisect without savepoints.
+	assert.Equal(t, []string{
+		"LOCK TABLES `city` WRITE;",
+		"/*!40000 ALTER TABLE `city` DISABLE KEYS */;",
+		"INSERT INTO `city`  VALUES (1, 'Kabul', 'AFG'), (2, 'Qandahar', 'AFG'), (3, 'Herat', 'AFG')",
+		"INSERT INTO `city`  VALUES (1, 'Kabul', 'AFG')",
+		"INSERT INTO `city`  VALUES (2, 'Qandahar', 'AFG'), (3, 'Herat', 'AFG')",
+		"INSERT INTO `city`  VALUES (2, 'Qandahar', 'AFG')",
+		"INSERT INTO `city`  VALUES (3, 'Herat', 'AFG')",
+		"/*!40000 ALTER TABLE `city` ENABLE KEYS */;",
+		"UNLOCK TABLES;",
+	}, db.sqls())
+
+	rej := d.Rejection()
+	if assert.Equal(t, 1, rej.Count) {
+		assert.Equal(t, 7, rej.Rejects[0].Line)
+	}
+
+	assert.Equal(t, map[string]int{"city": 2}, d.stats.snapshot())
+}
@@ -0,0 +1,404 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/lib/pq"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// bulkRows holds the literal rows of the same insert prefix.
+type bulkRows struct {
+	prefix  string
+	schema  string
+	table   string
+	columns []string
+	// target is the table and columns quoted as the statement.
+	target string
+	rows   [][]sqlx.Literal
+	// values are the original value tuples of rows,
+	// which is used to insert if bulk loading failed.
+	values []string
+	// lines are the source line numbers of rows.
+	lines []int
+}
+
+// batch returns the insert batch of the rows.
+func (b *bulkRows) batch() batch {
+	return batch{
+		prefix: b.prefix,
+		values: b.values,
+		lines:  b.lines,
+	}
+}
+
+// add appends the given rows with their value tuples of the given source line.
+func (b *bulkRows) add(rows [][]sqlx.Literal, values []string, line int) {
+	b.rows = append(b.rows, rows...)
+	b.values = append(b.values, values...)
+	for range values {
+		b.lines = append(b.lines, line)
+	}
+}
+
+// bulkable returns true if the inserting rows can be bulk loaded.
+func (in *dst) bulkable() bool {
+	if !in.bulk.Load() || in.sentry != nil {
+		return false
+	}
+
+	switch in.drv {
+	case sqlx.MySQLDialect, sqlx.PostgresDialect, sqlx.SQLServerDialect:
+		return true
+	}
+
+	return false
+}
+
+// bufferBulk buffers the literal rows of the given insert statement,
+// and flushes if reaches limitations.
+func (in *dst) bufferBulk(ctx context.Context, tbl string, bi sqlx.DMLBulkInsert) error {
+	in.orderPrefix(bi.Prefix)
+
+	b, exist := in.bulkBuf[bi.Prefix]
+	if !exist {
+		b = &bulkRows{
+			prefix:  bi.Prefix,
+			schema:  bi.Schema,
+			table:   bi.Table,
+			columns: bi.Columns,
+			target:  bi.Target,
+		}
+		in.bulkBuf[bi.Prefix] = b
+	}
+
+	in.touchTable(tbl)
+
+	b.add(bi.Rows, bi.Values, LineFrom(ctx))
+	in.bulkBufRows += len(bi.Rows)
+	in.bufOffset = in.offset
+	in.stats.buffer(in.bufRows + in.bulkBufRows)
+
+	// Flush if reaches limitations.
+	if in.bulkBufRows >= in.bufSegCap*in.dbConnMax {
+		return in.Flush(ctx)
+	}
+
+	return nil
+}
+
+// errBulkIncomplete indicates the bulk loading does not load all rows as inserting does,
+// e.g. MySQL skips the duplicate rows and converts the invalid values with warnings.
+var errBulkIncomplete = errors.New("bulk loading is incomplete")
+
+// flushBulk loads the given rows in a transaction,
+// inserts the rows instead if failed,
+// and stops bulk loading if the database does not allow,
+// other failures only fall back the given rows.
+func (in *dst) flushBulk(ctx context.Context, b *bulkRows) error {
+	in.stats.batch()
+	defer in.stats.elapse(in.bufTables[b.prefix], time.Now())
+
+	err := in.retry.Do(ctx, func(ctx context.Context) error {
+		tx, err := in.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = tx.Rollback() }()
+
+		err = in.loadBulk(ctx, tx, b)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err == nil {
+		in.stats.insert(in.bufTables[b.prefix], len(b.rows))
+		return nil
+	}
+
+	tflog.Warn(ctx, "Cannot bulk load, inserting instead", map[string]any{
+		"table": b.table,
+		"error": err.Error(),
+	})
+
+	for _, bb := range b.batch().split(in.bufSegCap) {
+		if ierr := in.execBatch(ctx, in.db, bb, false); ierr != nil {
+			return ierr
+		}
+	}
+
+	// Stop bulk loading as inserting works but bulk loading does not,
+	// e.g. the MySQL server disables local_infile,
+	// keep bulk loading if the rows are incomplete or invalid, which is not the fault of the protocol.
+	if isBulkRefused(err) {
+		in.bulk.Store(false)
+		in.stats.disableBulk(err)
+	}
+
+	return nil
+}
+
+// isBulkRefused returns true if the given error indicates the database does not allow bulk loading.
+func isBulkRefused(err error) bool {
+	// MySQL.
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		switch myErr.Number {
+		case 1148, // The used command is not allowed.
+			2068, // LOAD DATA LOCAL INFILE is rejected by the client.
+			3948: // Loading local data is disabled.
+			return true
+		}
+
+		return false
+	}
+
+	// Postgres.
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "42501", // Insufficient privilege.
+			"0A000": // Feature not supported.
+			return true
+		}
+
+		return false
+	}
+
+	// SQLServer.
+	var msErr mssql.Error
+	if errors.As(err, &msErr) {
+		// No permission to use the bulk load statement.
+		return msErr.Number == 4834
+	}
+
+	return false
+}
+
+// loadBulk loads the given rows with the fastest ingestion protocol of the database.
+func (in *dst) loadBulk(ctx context.Context, tx *stdsql.Tx, b *bulkRows) error {
+	switch in.drv {
+	case sqlx.PostgresDialect:
+		return loadBulkPostgres(ctx, tx, b)
+	case sqlx.MySQLDialect:
+		return loadBulkMySQL(ctx, tx, b)
+	case sqlx.SQLServerDialect:
+		return loadBulkSQLServer(ctx, tx, b)
+	}
+
+	return fmt.Errorf("cannot bulk load on %s", in.drv)
+}
+
+// loadBulkPostgres loads the given rows via COPY FROM STDIN.
+func loadBulkPostgres(ctx context.Context, tx *stdsql.Tx, b *bulkRows) error {
+	var sb strings.Builder
+
+	// Keep the quoting of the statement,
+	// the case of the unquoted identifiers is folded by the database.
+	sb.WriteString("COPY ")
+	sb.WriteString(b.target)
+	sb.WriteString(" FROM STDIN")
+
+	stmt, err := tx.PrepareContext(ctx, sb.String())
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = stmt.Close() }()
+
+	for _, r := range b.rows {
+		args := make([]any, len(r))
+
+		for i := range r {
+			if r[i].Kind != sqlx.NullLiteral {
+				args[i] = r[i].Value
+			}
+		}
+
+		if _, err = stmt.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+
+	_, err = stmt.ExecContext(ctx)
+
+	return err
+}
+
+var mysqlReaderSeq atomic.Uint64
+
+// loadBulkMySQL loads the given rows via LOAD DATA LOCAL INFILE,
+// the LOCAL modifier skips the duplicate rows and converts the invalid values with warnings,
+// so returns errBulkIncomplete if any row is not loaded or any warning is raised.
+func loadBulkMySQL(ctx context.Context, tx *stdsql.Tx, b *bulkRows) error {
+	var buf bytes.Buffer
+
+	for _, r := range b.rows {
+		for i := range r {
+			if i != 0 {
+				buf.WriteByte('\t')
+			}
+
+			switch r[i].Kind {
+			case sqlx.NullLiteral:
+				buf.WriteString(`\N`)
+			case sqlx.BooleanLiteral:
+				if r[i].Value == "t" {
+					buf.WriteByte('1')
+				} else {
+					buf.WriteByte('0')
+				}
+			default:
+				writeMySQLField(&buf, r[i].Value)
+			}
+		}
+
+		buf.WriteByte('\n')
+	}
+
+	name := "byteset-" + strconv.FormatUint(mysqlReaderSeq.Add(1), 10)
+
+	mysql.RegisterReaderHandler(name, func() io.Reader { return &buf })
+	defer mysql.DeregisterReaderHandler(name)
+
+	var sb strings.Builder
+
+	sb.WriteString("LOAD DATA LOCAL INFILE 'Reader::")
+	sb.WriteString(name)
+	sb.WriteString("' INTO TABLE ")
+
+	if b.schema != "" {
+		sb.WriteString(quoteMySQL(b.schema))
+		sb.WriteString(".")
+	}
+	sb.WriteString(quoteMySQL(b.table))
+	sb.WriteString(` CHARACTER SET utf8mb4 FIELDS TERMINATED BY '\t' ESCAPED BY '\\' LINES TERMINATED BY '\n'`)
+
+	if len(b.columns) != 0 {
+		sb.WriteString(" (")
+
+		for i := range b.columns {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(quoteMySQL(b.columns[i]))
+		}
+		sb.WriteString(")")
+	}
+
+	r, err := tx.ExecContext(ctx, sb.String())
+	if err != nil {
+		return err
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	var warnings int
+
+	err = tx.QueryRowContext(ctx, "SELECT @@warning_count").Scan(&warnings)
+	if err != nil {
+		return err
+	}
+
+	if n != int64(len(b.rows)) || warnings != 0 {
+		return fmt.Errorf("%w: loaded %d of %d rows with %d warnings",
+			errBulkIncomplete, n, len(b.rows), warnings)
+	}
+
+	return nil
+}
+
+func writeMySQLField(buf *bytes.Buffer, s string) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case 0:
+			buf.WriteString(`\0`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+}
+
+func quoteMySQL(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// loadBulkSQLServer loads the given rows via the TDS bulk copy,
+// the columns must be specified.
+func loadBulkSQLServer(ctx context.Context, tx *stdsql.Tx, b *bulkRows) error {
+	if len(b.columns) == 0 {
+		return fmt.Errorf("cannot bulk copy into %s without columns", b.table)
+	}
+
+	tbl := quoteSQLServer(b.table)
+	if b.schema != "" {
+		tbl = quoteSQLServer(b.schema) + "." + tbl
+	}
+
+	stmt, err := tx.PrepareContext(ctx, mssql.CopyIn(tbl, mssql.BulkOptions{}, b.columns...))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = stmt.Close() }()
+
+	for _, r := range b.rows {
+		args := make([]any, len(r))
+
+		for i := range r {
+			switch r[i].Kind {
+			case sqlx.NullLiteral:
+			case sqlx.BooleanLiteral:
+				args[i] = r[i].Value == "t"
+			case sqlx.IntegerLiteral:
+				v, err := strconv.ParseInt(r[i].Value, 10, 64)
+				if err != nil {
+					args[i] = r[i].Value
+				} else {
+					args[i] = v
+				}
+			default:
+				args[i] = r[i].Value
+			}
+		}
+
+		if _, err = stmt.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+
+	_, err = stmt.ExecContext(ctx)
+
+	return err
+}
+
+func quoteSQLServer(s string) string {
+	return "[" + strings.ReplaceAll(s, "]", "]]") + "]"
+}
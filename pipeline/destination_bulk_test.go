@@ -0,0 +1,159 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+func TestWriteMySQLField(t *testing.T) {
+	tc := []struct {
+		given    string
+		expected string
+	}{
+		{
+			given:    "Lanús",
+			expected: "Lanús",
+		},
+		{
+			given:    "a\tb\nc\r\\d\x00",
+			expected: `a\tb\nc\r\\d\0`,
+		},
+	}
+
+	for i, c := range tc {
+		t.Run("case "+strconv.Itoa(i), func(t *testing.T) {
+			var actual bytes.Buffer
+			writeMySQLField(&actual, c.given)
+			assert.Equal(t, c.expected, actual.String())
+		})
+	}
+}
+
+func TestDestination_flushBulk(t *testing.T) {
+	const insert = `INSERT INTO "Sales"."Customers" ("ID", Name) VALUES (1, 'a'), (2, 'b');`
+
+	tc := []struct {
+		name            string
+		given           error
+		expectedInserts int
+		expectedBulk    bool
+	}{
+		{
+			name:            "loaded",
+			given:           nil,
+			expectedInserts: 0,
+			expectedBulk:    true,
+		},
+		{
+			name:            "not permitted",
+			given:           &pq.Error{Code: "42501"},
+			expectedInserts: 1,
+			expectedBulk:    false,
+		},
+		{
+			name:            "invalid rows",
+			given:           &pq.Error{Code: "23505"},
+			expectedInserts: 1,
+			expectedBulk:    true,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			db := &fakeDB{
+				fail: func(sql string) error {
+					if strings.HasPrefix(sql, "COPY ") {
+						return c.given
+					}
+
+					return nil
+				},
+			}
+
+			in := newFakeDestination(sqlx.PostgresDialect, db)
+			in.bulk.Store(true)
+
+			bi, ok := sqlx.Parse(sqlx.PostgresDialect, insert).AsDMLBulkInsert()
+			if !assert.True(t, ok) {
+				return
+			}
+
+			b := &bulkRows{
+				prefix:  bi.Prefix,
+				schema:  bi.Schema,
+				table:   bi.Table,
+				columns: bi.Columns,
+				target:  bi.Target,
+			}
+			b.add(bi.Rows, bi.Values, 1)
+
+			err := in.flushBulk(context.TODO(), b)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var inserts int
+
+			for _, s := range db.sqls() {
+				if strings.HasPrefix(s, "INSERT ") {
+					inserts++
+				}
+			}
+
+			// Keep the quoting of the statement.
+			assert.Contains(t, db.sqls(), `COPY "Sales"."Customers" ("ID", name) FROM STDIN`)
+			assert.Equal(t, c.expectedInserts, inserts)
+			assert.Equal(t, c.expectedBulk, in.bulk.Load())
+			assert.Equal(t, c.expectedBulk, in.Stats().BulkDisabled == "")
+		})
+	}
+}
+
+func TestIsBulkRefused(t *testing.T) {
+	tc := []struct {
+		given    error
+		expected bool
+	}{
+		{
+			given:    &mysql.MySQLError{Number: 3948},
+			expected: true,
+		},
+		{
+			given:    &mysql.MySQLError{Number: 1062},
+			expected: false,
+		},
+		{
+			given:    &pq.Error{Code: "42501"},
+			expected: true,
+		},
+		{
+			given:    &pq.Error{Code: "22P02"},
+			expected: false,
+		},
+		{
+			given:    mssql.Error{Number: 4834},
+			expected: true,
+		},
+		{
+			given:    errBulkIncomplete,
+			expected: false,
+		},
+	}
+
+	for i, c := range tc {
+		t.Run("case "+strconv.Itoa(i), func(t *testing.T) {
+			actual := isBulkRefused(c.given)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package pipeline
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+const checkpointTable = "byteset_checkpoints"
+
+// loadCheckpoint prepares the checkpoint table,
+// and returns the recorded offset of the digest.
+func (in *dst) loadCheckpoint(ctx context.Context) (int, error) {
+	var ddl string
+
+	switch in.drv {
+	default:
+		ddl = `CREATE TABLE IF NOT EXISTS ` + checkpointTable + ` (
+    digest      VARCHAR(64) NOT NULL PRIMARY KEY,
+    stmt_offset BIGINT      NOT NULL,
+    updated_at  TIMESTAMP   NOT NULL
+)`
+	case sqlx.SQLServerDialect:
+		ddl = `IF OBJECT_ID(N'` + checkpointTable + `', N'U') IS NULL
+CREATE TABLE ` + checkpointTable + ` (
+    digest      NVARCHAR(64) NOT NULL PRIMARY KEY,
+    stmt_offset BIGINT       NOT NULL,
+    updated_at  DATETIME2    NOT NULL
+)`
+	case sqlx.OracleDialect:
+		ddl = `BEGIN
+    EXECUTE IMMEDIATE 'CREATE TABLE ` + checkpointTable + ` (
+        digest      VARCHAR2(64) NOT NULL PRIMARY KEY,
+        stmt_offset NUMBER(19)   NOT NULL,
+        updated_at  TIMESTAMP    NOT NULL
+    )';
+EXCEPTION
+    WHEN OTHERS THEN
+        IF SQLCODE != -955 THEN RAISE; END IF;
+END;`
+	}
+
+	err := sqlx.Exec(ctx, in.db, ddl)
+	if err != nil {
+		return 0, fmt.Errorf("cannot create checkpoint table: %w", err)
+	}
+
+	var offset int
+
+	err = in.db.QueryRowContext(ctx,
+		`SELECT stmt_offset FROM `+checkpointTable+` WHERE digest = `+sqlx.Placeholder(in.drv, 1),
+		in.ckpt).
+		Scan(&offset)
+	if err != nil && !errors.Is(err, stdsql.ErrNoRows) {
+		return 0, fmt.Errorf("cannot query checkpoint: %w", err)
+	}
+
+	return offset, nil
+}
+
+// checkpoint records the current offset in a transaction,
+// it does nothing if disabled or inside the sentry session,
+// as the sentry session may be locking tables.
+func (in *dst) checkpoint(ctx context.Context) error {
+	if in.ckpt == "" || in.sentry != nil {
+		return nil
+	}
+
+	return in.retry.DoIdempotent(ctx, func(ctx context.Context) error {
+		return in.saveCheckpointInTx(ctx, in.offset)
+	})
+}
+
+func (in *dst) saveCheckpointInTx(ctx context.Context, offset int) error {
+	tx, err := in.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	err = in.saveCheckpoint(ctx, tx, offset)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// flushWithCheckpoint flushes the given units in order and records the buffered offset,
+// all of them are committed in the same transaction.
+func (in *dst) flushWithCheckpoint(ctx context.Context, units []flushUnit) (err error) {
+	tx, err := in.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Uncount the inserted rows if rolled back.
+	rows := in.stats.snapshot()
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			in.stats.restore(rows)
+		}
+	}()
+
+	for _, u := range units {
+		if u.bulk != nil {
+			in.stats.batch()
+
+			start := time.Now()
+
+			err = in.loadBulk(ctx, tx, u.bulk)
+			if err != nil {
+				return err
+			}
+
+			in.stats.insert(u.table, len(u.bulk.rows))
+			in.stats.elapse(u.table, start)
+		}
+
+		for i := range u.batches {
+			err = in.execBatch(ctx, tx, u.batches[i], true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = in.saveCheckpoint(ctx, tx, in.bufOffset)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withoutBulk returns the given units with the bulk rows converted into the insert batches of the given capacity,
+// and true if any unit is converted.
+func withoutBulk(units []flushUnit, segCap int) ([]flushUnit, bool) {
+	var (
+		r  = make([]flushUnit, len(units))
+		ok bool
+	)
+
+	for i, u := range units {
+		r[i] = u
+
+		if u.bulk == nil {
+			continue
+		}
+
+		r[i].bulk = nil
+		r[i].batches = append(u.bulk.batch().split(segCap), u.batches...)
+		ok = true
+	}
+
+	return r, ok
+}
+
+func (in *dst) saveCheckpoint(ctx context.Context, ex sqlx.Executor, offset int) error {
+	err := sqlx.Exec(ctx, ex,
+		`DELETE FROM `+checkpointTable+` WHERE digest = `+sqlx.Placeholder(in.drv, 1),
+		in.ckpt)
+	if err != nil {
+		return fmt.Errorf("cannot clean checkpoint: %w", err)
+	}
+
+	err = sqlx.Exec(ctx, ex,
+		`INSERT INTO `+checkpointTable+` (digest, stmt_offset, updated_at) VALUES (`+
+			sqlx.Placeholder(in.drv, 1)+`, `+sqlx.Placeholder(in.drv, 2)+`, CURRENT_TIMESTAMP)`,
+		in.ckpt, offset)
+	if err != nil {
+		return fmt.Errorf("cannot save checkpoint: %w", err)
+	}
+
+	tflog.Trace(ctx, "Checkpoint", map[string]any{"offset": offset})
+
+	return nil
+}
+
+// clearCheckpoint removes the record of the digest,
+// so that the next piping starts from the beginning.
+func (in *dst) clearCheckpoint(ctx context.Context) error {
+	err := sqlx.Exec(ctx, in.db,
+		`DELETE FROM `+checkpointTable+` WHERE digest = `+sqlx.Placeholder(in.drv, 1),
+		in.ckpt)
+	if err != nil {
+		return fmt.Errorf("cannot clear checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithoutBulk(t *testing.T) {
+	var b batch
+	b.prefix = "INSERT INTO t "
+	b.add([]string{"(4)"}, 4)
+
+	units := []flushUnit{
+		{
+			table: "s",
+			batches: []batch{
+				{prefix: "INSERT INTO s ", values: []string{"(1)"}, lines: []int{1}},
+			},
+		},
+		{
+			table: "t",
+			bulk: &bulkRows{
+				prefix: "INSERT INTO t ",
+				values: []string{"(1)", "(2)", "(3)"},
+				lines:  []int{1, 2, 3},
+			},
+			batches: []batch{b},
+		},
+	}
+
+	actual, ok := withoutBulk(units, 2)
+	if assert.True(t, ok) {
+		assert.Equal(t, units[0], actual[0])
+		assert.Nil(t, actual[1].bulk)
+
+		var sqls []string
+		for _, b := range actual[1].batches {
+			sqls = append(sqls, b.sql())
+		}
+
+		assert.Equal(t, []string{
+			"INSERT INTO t VALUES (1), (2)",
+			"INSERT INTO t VALUES (3)",
+			"INSERT INTO t VALUES (4)",
+		}, sqls)
+	}
+
+	assert.NotNil(t, units[1].bulk, "the given units are kept")
+
+	_, ok = withoutBulk(units[:1], 2)
+	assert.False(t, ok)
+}
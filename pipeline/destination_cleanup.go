@@ -0,0 +1,104 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+func (in *dst) Truncate(ctx context.Context, tables []string) error {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	var sqls []string
+
+	switch in.drv {
+	case sqlx.MySQLDialect:
+		sqls = append(sqls, "SET FOREIGN_KEY_CHECKS = 0")
+		for _, t := range reverse(tables) {
+			sqls = append(sqls, "TRUNCATE TABLE "+t)
+		}
+		sqls = append(sqls, "SET FOREIGN_KEY_CHECKS = 1")
+	case sqlx.PostgresDialect:
+		// Truncate all in one statement to satisfy the foreign keys between them.
+		sqls = append(sqls, "TRUNCATE TABLE "+strings.Join(tables, ", "))
+	default:
+		// Truncating is not allowed on the referenced tables,
+		// so delete from the referencing ones first.
+		for _, t := range reverse(tables) {
+			sqls = append(sqls, "DELETE FROM "+t)
+		}
+	}
+
+	return in.cleanup(ctx, sqls)
+}
+
+func (in *dst) Drop(ctx context.Context, tables []string) error {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	var sqls []string
+
+	switch in.drv {
+	case sqlx.MySQLDialect:
+		sqls = append(sqls, "SET FOREIGN_KEY_CHECKS = 0")
+		for _, t := range reverse(tables) {
+			sqls = append(sqls, "DROP TABLE IF EXISTS "+t)
+		}
+		sqls = append(sqls, "SET FOREIGN_KEY_CHECKS = 1")
+	case sqlx.PostgresDialect:
+		// Drop all in one statement to satisfy the foreign keys between them.
+		sqls = append(sqls, "DROP TABLE IF EXISTS "+strings.Join(tables, ", "))
+	case sqlx.OracleDialect:
+		for _, t := range reverse(tables) {
+			sqls = append(sqls, `BEGIN
+    EXECUTE IMMEDIATE 'DROP TABLE `+t+` CASCADE CONSTRAINTS';
+EXCEPTION
+    WHEN OTHERS THEN
+        IF SQLCODE != -942 THEN RAISE; END IF;
+END;`)
+		}
+	default:
+		for _, t := range reverse(tables) {
+			sqls = append(sqls, "DROP TABLE IF EXISTS "+t)
+		}
+	}
+
+	return in.cleanup(ctx, sqls)
+}
+
+// cleanup executes the given sqls in one session.
+func (in *dst) cleanup(ctx context.Context, sqls []string) error {
+	if err := in.Flush(ctx); err != nil {
+		return err
+	}
+
+	conn, err := in.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	for i := range sqls {
+		err = sqlx.Exec(ctx, conn, sqls[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reverse returns a reversed copy of the given strings.
+func reverse(ss []string) []string {
+	r := make([]string, len(ss))
+	for i := range ss {
+		r[len(ss)-1-i] = ss[i]
+	}
+
+	return r
+}
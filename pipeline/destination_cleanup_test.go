@@ -0,0 +1,30 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+func TestDestination_Created(t *testing.T) {
+	d := &dst{
+		drv:     sqlx.MySQLDialect,
+		touched: map[string]struct{}{},
+		created: map[string]struct{}{},
+	}
+
+	for _, sql := range []string{
+		"INSERT INTO existing (id) VALUES (1)",
+		"CREATE TABLE orders (id INT)",
+		"CREATE TABLE IF NOT EXISTS customers (id INT)",
+		"INSERT INTO orders (id) VALUES (1)",
+		"CREATE TABLE items (id INT)",
+	} {
+		d.touch(sqlx.Parse(d.drv, sql))
+	}
+
+	assert.Equal(t, []string{"existing", "orders", "customers", "items"}, d.Touched())
+	assert.Equal(t, []string{"orders", "items"}, d.Created())
+}
@@ -0,0 +1,392 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/lib/pq"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// fastLoadInits returns the statements to relax the checking of each session,
+// the SQLServer and Oracle are relaxed by table instead.
+func fastLoadInits(drv string) []string {
+	switch drv {
+	case sqlx.MySQLDialect:
+		return []string{"SET FOREIGN_KEY_CHECKS = 0, UNIQUE_CHECKS = 0"}
+	case sqlx.PostgresDialect:
+		return []string{"SET session_replication_role = replica"}
+	}
+
+	return nil
+}
+
+// relax disables the constraints and triggers of the table written by the given sql,
+// only for SQLServer and Oracle.
+func (in *dst) relax(ctx context.Context, sqlp sqlx.Parsed) error {
+	if !in.fastLoad || (in.drv != sqlx.SQLServerDialect && in.drv != sqlx.OracleDialect) {
+		return nil
+	}
+
+	tbl, ok := sqlp.Table()
+	if !ok || tbl == "" {
+		return nil
+	}
+
+	if _, exist := in.relaxed[tbl]; exist {
+		return nil
+	}
+
+	// Disabling the constraints is DDL on Oracle,
+	// which commits the opening transaction of source implicitly.
+	if in.drv == sqlx.OracleDialect && in.sentry != nil {
+		return fmt.Errorf("cannot fast load %s inside transaction on Oracle, "+
+			"which commits the transaction implicitly at disabling the constraints", tbl)
+	}
+
+	qtbl := quoteTable(in.drv, tbl)
+
+	var stmts []string
+
+	switch in.drv {
+	case sqlx.SQLServerDialect:
+		in.relaxed[tbl] = nil
+		stmts = []string{
+			"ALTER TABLE " + qtbl + " NOCHECK CONSTRAINT ALL",
+			"DISABLE TRIGGER ALL ON " + qtbl,
+		}
+	case sqlx.OracleDialect:
+		cs, err := in.oracleConstraints(ctx, tbl)
+		if err != nil {
+			return fmt.Errorf("cannot query constraints of %s: %w", tbl, err)
+		}
+
+		in.relaxed[tbl] = cs
+		for _, c := range cs {
+			stmts = append(stmts, "ALTER TABLE "+qtbl+" DISABLE CONSTRAINT "+c)
+		}
+		stmts = append(stmts, "ALTER TABLE "+qtbl+" DISABLE ALL TRIGGERS")
+	}
+
+	in.relaxedOrder = append(in.relaxedOrder, tbl)
+
+	tflog.Debug(ctx, "Disabling constraints and triggers", map[string]any{"table": tbl})
+
+	for _, s := range stmts {
+		if err := in.execAlone(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restore enables the constraints and triggers disabled by relax and validates the integrity,
+// or validates the integrity of the touched tables on MySQL and Postgres.
+func (in *dst) restore(ctx context.Context) error {
+	if !in.fastLoad {
+		return nil
+	}
+
+	switch in.drv {
+	case sqlx.MySQLDialect, sqlx.PostgresDialect:
+		return in.verify(ctx)
+	}
+
+	for len(in.relaxedOrder) != 0 {
+		tbl := in.relaxedOrder[0]
+		qtbl := quoteTable(in.drv, tbl)
+
+		var stmts []string
+
+		switch in.drv {
+		case sqlx.SQLServerDialect:
+			stmts = []string{
+				"ALTER TABLE " + qtbl + " WITH CHECK CHECK CONSTRAINT ALL",
+				"ENABLE TRIGGER ALL ON " + qtbl,
+			}
+		case sqlx.OracleDialect:
+			for _, c := range in.relaxed[tbl] {
+				stmts = append(stmts, "ALTER TABLE "+qtbl+" ENABLE VALIDATE CONSTRAINT "+c)
+			}
+			stmts = append(stmts, "ALTER TABLE "+qtbl+" ENABLE ALL TRIGGERS")
+		}
+
+		for _, s := range stmts {
+			if err := in.execAlone(ctx, s); err != nil {
+				return fmt.Errorf("cannot validate integrity of %s: %w", tbl, err)
+			}
+		}
+
+		delete(in.relaxed, tbl)
+		in.relaxedOrder = in.relaxedOrder[1:]
+	}
+
+	return nil
+}
+
+// unrelax enables the constraints and triggers disabled by relax without validating,
+// which is used to recover from failure.
+func (in *dst) unrelax() {
+	if len(in.relaxedOrder) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	for _, tbl := range in.relaxedOrder {
+		qtbl := quoteTable(in.drv, tbl)
+
+		var stmts []string
+
+		switch in.drv {
+		case sqlx.SQLServerDialect:
+			stmts = []string{
+				"ALTER TABLE " + qtbl + " CHECK CONSTRAINT ALL",
+				"ENABLE TRIGGER ALL ON " + qtbl,
+			}
+		case sqlx.OracleDialect:
+			for _, c := range in.relaxed[tbl] {
+				stmts = append(stmts, "ALTER TABLE "+qtbl+" ENABLE NOVALIDATE CONSTRAINT "+c)
+			}
+			stmts = append(stmts, "ALTER TABLE "+qtbl+" ENABLE ALL TRIGGERS")
+		}
+
+		for _, s := range stmts {
+			if _, err := in.db.ExecContext(ctx, s); err != nil {
+				tflog.Warn(ctx, "Cannot enable constraints or triggers", map[string]any{
+					"table": tbl,
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+
+	in.relaxed = map[string][]string{}
+	in.relaxedOrder = nil
+}
+
+// verify validates the foreign keys and the unique keys referring to the touched tables,
+// as MySQL and Postgres do not validate the existing rows after enabling the checking,
+// the validation runs in the sentry session if found, which can see the uncommitted rows.
+func (in *dst) verify(ctx context.Context) error {
+	touched := make(map[string]struct{}, len(in.touchedOrder))
+	for _, t := range in.touchedOrder {
+		touched[normalizeTable(t)] = struct{}{}
+	}
+
+	if len(touched) == 0 {
+		return nil
+	}
+
+	isTouched := func(t string) bool {
+		_, ok := touched[normalizeTable(t)]
+		return ok
+	}
+
+	var q sqlx.Queryer = in.db
+	if in.sentry != nil {
+		q = in.sentry
+	}
+
+	fks, err := sqlx.ForeignKeyColumns(ctx, in.drv, q)
+	if err != nil {
+		return fmt.Errorf("cannot validate integrity: %w", err)
+	}
+
+	for _, fk := range fks {
+		if !isTouched(fk.Table) && !isTouched(fk.RefTable) {
+			continue
+		}
+
+		violated, err := exists(ctx, q, foreignKeyViolation(in.drv, fk))
+		if err != nil {
+			return fmt.Errorf("cannot validate foreign key %s of %s: %w", fk.Name, fk.Table, err)
+		}
+
+		if violated {
+			return fmt.Errorf("foreign key %s of %s is violated", fk.Name, fk.Table)
+		}
+	}
+
+	if in.drv != sqlx.MySQLDialect {
+		return nil
+	}
+
+	uks, err := sqlx.UniqueKeyColumns(ctx, in.drv, q)
+	if err != nil {
+		return fmt.Errorf("cannot validate integrity: %w", err)
+	}
+
+	for _, uk := range uks {
+		if !isTouched(uk.Table) {
+			continue
+		}
+
+		violated, err := exists(ctx, q, uniqueKeyViolation(in.drv, uk))
+		if err != nil {
+			return fmt.Errorf("cannot validate unique key %s of %s: %w", uk.Name, uk.Table, err)
+		}
+
+		if violated {
+			return fmt.Errorf("unique key %s of %s is violated", uk.Name, uk.Table)
+		}
+	}
+
+	return nil
+}
+
+// exists returns true if the given query returns any row.
+func exists(ctx context.Context, q sqlx.Queryer, query string) (bool, error) {
+	rs, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = rs.Close() }()
+
+	if rs.Next() {
+		return true, nil
+	}
+
+	return false, rs.Err()
+}
+
+// execAlone executes the given sql in sentry session if found,
+// or executes in one session.
+func (in *dst) execAlone(ctx context.Context, sql string) error {
+	if in.sentry != nil {
+		return sqlx.Exec(ctx, in.sentry, sql)
+	}
+
+	return in.retry.Exec(ctx, in.db, sql)
+}
+
+// oracleConstraints returns the quoted names of the enabled foreign keys of the given table.
+func (in *dst) oracleConstraints(ctx context.Context, tbl string) ([]string, error) {
+	n := tbl
+	if i := strings.LastIndex(n, "."); i >= 0 {
+		n = n[i+1:]
+	}
+
+	if strings.HasPrefix(n, `"`) || strings.HasPrefix(n, "`") {
+		n = strings.Trim(n, "`\"")
+	} else {
+		n = strings.ToUpper(n)
+	}
+
+	rs, err := in.db.QueryContext(ctx,
+		`SELECT constraint_name FROM user_constraints
+WHERE table_name = :1 AND constraint_type = 'R' AND status = 'ENABLED'`, n)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rs.Close() }()
+
+	var cs []string
+
+	for rs.Next() {
+		var c string
+		if err = rs.Scan(&c); err != nil {
+			return nil, err
+		}
+
+		cs = append(cs, `"`+strings.ReplaceAll(c, `"`, `""`)+`"`)
+	}
+
+	return cs, rs.Err()
+}
+
+// foreignKeyViolation returns the query to find a row violates the given foreign key.
+func foreignKeyViolation(drv string, fk sqlx.ForeignKey) string {
+	var sb strings.Builder
+
+	sb.WriteString("SELECT 1 FROM ")
+	sb.WriteString(quoteIdentifier(drv, fk.Table))
+	sb.WriteString(" c WHERE ")
+
+	for i := range fk.Columns {
+		sb.WriteString("c.")
+		sb.WriteString(quoteIdentifier(drv, fk.Columns[i]))
+		sb.WriteString(" IS NOT NULL AND ")
+	}
+
+	sb.WriteString("NOT EXISTS (SELECT 1 FROM ")
+	sb.WriteString(quoteIdentifier(drv, fk.RefTable))
+	sb.WriteString(" p WHERE ")
+
+	for i := range fk.Columns {
+		if i != 0 {
+			sb.WriteString(" AND ")
+		}
+		sb.WriteString("p.")
+		sb.WriteString(quoteIdentifier(drv, fk.RefColumns[i]))
+		sb.WriteString(" = c.")
+		sb.WriteString(quoteIdentifier(drv, fk.Columns[i]))
+	}
+
+	sb.WriteString(") LIMIT 1")
+
+	return sb.String()
+}
+
+// uniqueKeyViolation returns the query to find the duplicated rows of the given unique key.
+func uniqueKeyViolation(drv string, uk sqlx.UniqueKey) string {
+	cs := make([]string, len(uk.Columns))
+	for i := range uk.Columns {
+		cs[i] = quoteIdentifier(drv, uk.Columns[i])
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("SELECT 1 FROM ")
+	sb.WriteString(quoteIdentifier(drv, uk.Table))
+	sb.WriteString(" WHERE ")
+
+	for i := range cs {
+		if i != 0 {
+			sb.WriteString(" AND ")
+		}
+		sb.WriteString(cs[i])
+		sb.WriteString(" IS NOT NULL")
+	}
+
+	sb.WriteString(" GROUP BY ")
+	sb.WriteString(strings.Join(cs, ", "))
+	sb.WriteString(" HAVING COUNT(*) > 1 LIMIT 1")
+
+	return sb.String()
+}
+
+func quoteIdentifier(drv, s string) string {
+	switch drv {
+	case sqlx.MySQLDialect:
+		return quoteMySQL(s)
+	case sqlx.PostgresDialect:
+		return pq.QuoteIdentifier(s)
+	case sqlx.SQLServerDialect:
+		return quoteSQLServer(s)
+	}
+
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// quoteTable returns the given parsed table name in the quoting of the given driver,
+// the unquoted parts are kept as they are.
+func quoteTable(drv, tbl string) string {
+	ps := strings.Split(tbl, ".")
+
+	for i, p := range ps {
+		if q := strings.Trim(p, "`\"[]"); q != p {
+			ps[i] = quoteIdentifier(drv, q)
+		}
+	}
+
+	return strings.Join(ps, ".")
+}
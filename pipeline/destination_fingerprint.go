@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"context"
+	stdsql "database/sql"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// absentFingerprint indicates the table cannot be queried,
+// like the table has been dropped.
+const absentFingerprint = "-"
+
+func (in *dst) Fingerprint(ctx context.Context, tables []string, checksum bool) (map[string]string, error) {
+	if err := in.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	fps := make(map[string]string, len(tables))
+
+	for _, t := range tables {
+		fp, err := in.fingerprint(ctx, t, checksum)
+		if err != nil {
+			tflog.Debug(ctx, "Cannot fingerprint table",
+				map[string]any{"table": t, "error": err})
+
+			fp = absentFingerprint
+		}
+
+		fps[t] = fp
+	}
+
+	return fps, nil
+}
+
+func (in *dst) fingerprint(ctx context.Context, table string, checksum bool) (string, error) {
+	var cnt int64
+
+	err := in.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).
+		Scan(&cnt)
+	if err != nil {
+		return "", err
+	}
+
+	fp := strconv.FormatInt(cnt, 10)
+
+	if !checksum {
+		return fp, nil
+	}
+
+	var sum stdsql.NullString
+
+	switch in.drv {
+	case sqlx.MySQLDialect:
+		var tbl string
+
+		err = in.db.QueryRowContext(ctx, "CHECKSUM TABLE "+table).
+			Scan(&tbl, &sum)
+	case sqlx.PostgresDialect:
+		// Sum the hash of each row to be order-independent.
+		err = in.db.QueryRowContext(ctx,
+			"SELECT COALESCE(SUM(hashtext(x::text)::numeric), 0)::text FROM "+table+" AS x").
+			Scan(&sum)
+	case sqlx.SQLServerDialect:
+		err = in.db.QueryRowContext(ctx,
+			"SELECT CAST(CHECKSUM_AGG(BINARY_CHECKSUM(*)) AS NVARCHAR(32)) FROM "+table).
+			Scan(&sum)
+	default:
+		// Count only as checksum is not supported.
+		return fp, nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return fp + ":" + sum.String, nil
+}
@@ -0,0 +1,142 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// flushUnit holds the buffered insert batches or bulk rows of a table.
+type flushUnit struct {
+	table   string
+	batches []batch
+	bulk    *bulkRows
+}
+
+// statements returns the insert statements of the unit.
+func (u flushUnit) statements() []string {
+	sqls := make([]string, 0, len(u.batches)+1)
+
+	if u.bulk != nil {
+		sqls = append(sqls, u.bulk.batch().sql())
+	}
+
+	for i := range u.batches {
+		sqls = append(sqls, u.batches[i].sql())
+	}
+
+	return sqls
+}
+
+// orderPrefix records the order of the given insert prefix if it is not buffered.
+func (in *dst) orderPrefix(p string) {
+	if _, exist := in.buf[p]; exist {
+		return
+	}
+
+	if _, exist := in.bulkBuf[p]; exist {
+		return
+	}
+
+	in.bufOrder = append(in.bufOrder, p)
+}
+
+// levels groups the given units in order,
+// the units of the same level can be flushed in parallel,
+// a unit is placed after all previous units which are related to it by foreign key.
+func (in *dst) levels(ctx context.Context, units []flushUnit) [][]flushUnit {
+	if len(units) == 0 {
+		return nil
+	}
+
+	tables := map[string]struct{}{}
+	for i := range units {
+		tables[units[i].table] = struct{}{}
+	}
+
+	if len(tables) > 1 && !in.fksLoaded {
+		in.loadForeignKeys(ctx)
+	}
+
+	var (
+		ls = make([]int, len(units))
+		r  [][]flushUnit
+	)
+
+	for i := range units {
+		for j := 0; j < i; j++ {
+			if ls[i] <= ls[j] && in.related(units[i].table, units[j].table) {
+				ls[i] = ls[j] + 1
+			}
+		}
+
+		if ls[i] == len(r) {
+			r = append(r, nil)
+		}
+		r[ls[i]] = append(r[ls[i]], units[i])
+	}
+
+	return r
+}
+
+// related returns true if one of the given tables references the other,
+// or the relationship is unknown.
+func (in *dst) related(a, b string) bool {
+	if a == "" || b == "" || in.fks == nil {
+		return true
+	}
+
+	a, b = normalizeTable(a), normalizeTable(b)
+	if a == b {
+		return false
+	}
+
+	if _, ok := in.fks[a][b]; ok {
+		return true
+	}
+
+	_, ok := in.fks[b][a]
+
+	return ok
+}
+
+// loadForeignKeys loads the foreign keys of the destination,
+// tables are treated as related to each other if failed.
+func (in *dst) loadForeignKeys(ctx context.Context) {
+	in.fksLoaded = true
+
+	fks, err := sqlx.ForeignKeys(ctx, in.drv, in.db)
+	if err != nil {
+		tflog.Debug(ctx, "Cannot load foreign keys, flushing tables in sequence",
+			map[string]any{"error": err.Error()})
+
+		in.fks = nil
+
+		return
+	}
+
+	in.fks = map[string]map[string]struct{}{}
+
+	for t, rs := range fks {
+		t = normalizeTable(t)
+		if in.fks[t] == nil {
+			in.fks[t] = map[string]struct{}{}
+		}
+
+		for _, r := range rs {
+			in.fks[t][normalizeTable(r)] = struct{}{}
+		}
+	}
+}
+
+// normalizeTable returns the lower case name of the given table without schema and quotes.
+func normalizeTable(t string) string {
+	if i := strings.LastIndex(t, "."); i >= 0 {
+		t = t[i+1:]
+	}
+
+	return strings.ToLower(strings.Trim(t, "`\"[]"))
+}
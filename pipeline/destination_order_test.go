@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDestination_levels(t *testing.T) {
+	type input struct {
+		fks    map[string]map[string]struct{}
+		tables []string
+	}
+
+	tc := []struct {
+		given    input
+		expected [][]string
+	}{
+		{
+			given: input{
+				fks: map[string]map[string]struct{}{
+					"orders": {"customers": {}},
+					"items":  {"orders": {}, "products": {}},
+				},
+				tables: []string{"customers", "products", "orders", "`items`", "logs", "public.customers"},
+			},
+			expected: [][]string{
+				{"customers", "products", "logs"},
+				{"orders"},
+				{"`items`", "public.customers"},
+			},
+		},
+		{
+			given: input{
+				fks:    nil,
+				tables: []string{"a", "b", "a"},
+			},
+			expected: [][]string{
+				{"a"},
+				{"b"},
+				{"a"},
+			},
+		},
+	}
+
+	for i, c := range tc {
+		t.Run("case "+strconv.Itoa(i), func(t *testing.T) {
+			d := &dst{fks: c.given.fks, fksLoaded: true}
+
+			units := make([]flushUnit, len(c.given.tables))
+			for j := range c.given.tables {
+				units[j] = flushUnit{table: c.given.tables[j]}
+			}
+
+			var actual [][]string
+			for _, lvl := range d.levels(context.TODO(), units) {
+				var ts []string
+				for _, u := range lvl {
+					ts = append(ts, u.table)
+				}
+				actual = append(actual, ts)
+			}
+
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,477 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// stagedInsert is the spilled record of an insert statement.
+type stagedInsert struct {
+	Prefix  string           `json:"p"`
+	Values  []string         `json:"v"`
+	Schema  string           `json:"s,omitempty"`
+	Table   string           `json:"t,omitempty"`
+	Columns []string         `json:"c,omitempty"`
+	Target  string           `json:"g,omitempty"`
+	Rows    [][]sqlx.Literal `json:"r,omitempty"`
+	Line    int              `json:"l,omitempty"`
+}
+
+// stagedSpan is a contiguous range of the spill file.
+type stagedSpan struct {
+	off int64
+	n   int64
+}
+
+// stagedTable indexes the spilled records of a table.
+type stagedTable struct {
+	table string
+	spans []stagedSpan
+}
+
+// staging spills the inserting statements into a temporary file grouped by table,
+// which are loaded at next flushing in order of the table dependencies.
+type staging struct {
+	file   *os.File
+	w      *bufio.Writer
+	off    int64
+	tables map[string]*stagedTable
+	order  []string
+}
+
+// stageable returns true if the inserting statements can be staged.
+func (in *dst) stageable() bool {
+	return in.parallel && in.sentry == nil && in.ckpt == ""
+}
+
+// stageInsert stages the given statement if it is an insert statement.
+func (in *dst) stageInsert(ctx context.Context, sqlp sqlx.Parsed) (bool, error) {
+	r := stagedInsert{Line: LineFrom(ctx)}
+
+	if in.bulkable() {
+		bi, ok := sqlp.AsDMLBulkInsert()
+		if !ok {
+			return false, nil
+		}
+
+		r.Prefix, r.Values = bi.Prefix, bi.Values
+
+		if bi.Rows != nil && (in.drv != sqlx.SQLServerDialect || len(bi.Columns) != 0) {
+			r.Schema = bi.Schema
+			r.Table = bi.Table
+			r.Columns = bi.Columns
+			r.Target = bi.Target
+			r.Rows = bi.Rows
+		}
+	} else {
+		inst, ok := sqlp.AsDMLInsert()
+		if !ok {
+			return false, nil
+		}
+
+		r.Prefix, r.Values = inst.Prefix, inst.Values
+	}
+
+	tbl, exist := in.bufTables[r.Prefix]
+	if !exist {
+		tbl, _ = sqlp.Table()
+		in.bufTables[r.Prefix] = tbl
+	}
+
+	return true, in.stage(tbl, r)
+}
+
+// stage spills the given insert statement into the staging file.
+func (in *dst) stage(tbl string, r stagedInsert) error {
+	if in.stg == nil {
+		f, err := os.CreateTemp("", "byteset-staging-*")
+		if err != nil {
+			return err
+		}
+
+		in.stg = &staging{
+			file:   f,
+			w:      bufio.NewWriter(f),
+			tables: map[string]*stagedTable{},
+		}
+	}
+
+	bs, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	var hdr [binary.MaxVarintLen64]byte
+
+	hn := binary.PutUvarint(hdr[:], uint64(len(bs)))
+
+	if _, err = in.stg.w.Write(hdr[:hn]); err != nil {
+		return err
+	}
+
+	if _, err = in.stg.w.Write(bs); err != nil {
+		return err
+	}
+
+	n := int64(hn + len(bs))
+
+	st, exist := in.stg.tables[tbl]
+	if !exist {
+		st = &stagedTable{table: tbl}
+		in.stg.tables[tbl] = st
+		in.stg.order = append(in.stg.order, tbl)
+	}
+
+	// Extend the latest span if contiguous,
+	// which is common as the dumping tools group the statements by table.
+	if l := len(st.spans) - 1; l >= 0 && st.spans[l].off+st.spans[l].n == in.stg.off {
+		st.spans[l].n += n
+	} else {
+		st.spans = append(st.spans, stagedSpan{off: in.stg.off, n: n})
+	}
+	in.stg.off += n
+
+	in.touchTable(tbl)
+	in.bufOffset = in.offset
+
+	return nil
+}
+
+// closeStaging removes the staging file.
+func (in *dst) closeStaging() {
+	if in.stg == nil {
+		return
+	}
+
+	_ = in.stg.file.Close()
+	_ = os.Remove(in.stg.file.Name())
+	in.stg = nil
+}
+
+// flushStaged loads the staged tables concurrently,
+// a table is loaded after all tables it depends on have been loaded,
+// at most conn_max tables are loaded at the same time and their batches share conn_max sessions.
+func (in *dst) flushStaged(ctx context.Context) error {
+	if in.stg == nil {
+		return nil
+	}
+
+	stg := in.stg
+	in.stg = nil
+
+	defer func() {
+		_ = stg.file.Close()
+		_ = os.Remove(stg.file.Name())
+	}()
+
+	if err := stg.w.Flush(); err != nil {
+		return err
+	}
+
+	deps := in.dependencies(ctx, stg.order)
+
+	var (
+		dependents = map[string][]string{}
+		waiting    = map[string]int{}
+	)
+
+	for _, t := range stg.order {
+		waiting[t] = len(deps[t])
+		for _, d := range deps[t] {
+			dependents[d] = append(dependents[d], t)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		errOnce  sync.Once
+		firstErr error
+		fail     = func(err error) {
+			errOnce.Do(func() {
+				firstErr = err
+				cancel()
+			})
+		}
+	)
+
+	// Execute the batches in conn_max sessions.
+	var (
+		jobs    = make(chan stagedJob)
+		workers sync.WaitGroup
+	)
+
+	for i := 0; i < in.dbConnMax; i++ {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			for j := range jobs {
+				if ctx.Err() == nil {
+					if err := j.exec(ctx); err != nil {
+						fail(err)
+					}
+				}
+				j.wg.Done()
+			}
+		}()
+	}
+
+	// Schedule the tables in order of dependencies.
+	var (
+		done     = make(chan string)
+		started  = map[string]bool{}
+		ready    []string
+		active   int
+		finished int
+	)
+
+	for _, t := range stg.order {
+		if waiting[t] == 0 {
+			ready = append(ready, t)
+		}
+	}
+
+	start := func(t string) {
+		started[t] = true
+		active++
+
+		st := stg.tables[t]
+
+		go func() {
+			if err := in.feedStaged(ctx, stg.file, st, jobs); err != nil {
+				fail(err)
+			}
+			done <- st.table
+		}()
+	}
+
+	for finished < len(stg.order) {
+		for len(ready) != 0 && active < in.dbConnMax && ctx.Err() == nil {
+			start(ready[0])
+			ready = ready[1:]
+		}
+
+		if active == 0 {
+			if ctx.Err() != nil {
+				break
+			}
+
+			// Break the cyclic dependencies by loading the first pending table.
+			for _, t := range stg.order {
+				if !started[t] {
+					tflog.Debug(ctx, "Loading table with cyclic dependencies", map[string]any{"table": t})
+					ready = append(ready, t)
+
+					break
+				}
+			}
+
+			continue
+		}
+
+		t := <-done
+		active--
+		finished++
+
+		for _, d := range dependents[t] {
+			waiting[d]--
+			if waiting[d] == 0 && !started[d] {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	close(jobs)
+	workers.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return ctx.Err()
+}
+
+// stagedJob is a batch of a staged table.
+type stagedJob struct {
+	exec func(ctx context.Context) error
+	wg   *sync.WaitGroup
+}
+
+// feedStaged reads the records of the given staged table,
+// sends the batches to the given jobs and waits for their completion.
+func (in *dst) feedStaged(ctx context.Context, f *os.File, st *stagedTable, jobs chan<- stagedJob) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	send := func(exec func(ctx context.Context) error) bool {
+		wg.Add(1)
+
+		select {
+		case jobs <- stagedJob{exec: exec, wg: &wg}:
+			return true
+		case <-ctx.Done():
+			wg.Done()
+			return false
+		}
+	}
+
+	var (
+		bat  batch
+		bulk *bulkRows
+	)
+
+	flush := func() bool {
+		if len(bat.values) != 0 {
+			b := bat
+			bat = batch{prefix: b.prefix}
+
+			if !send(func(ctx context.Context) error { return in.execBatch(ctx, in.db, b, false) }) {
+				return false
+			}
+		}
+
+		if bulk != nil {
+			b := bulk
+			bulk = nil
+
+			if !send(func(ctx context.Context) error { return in.flushBulk(ctx, b) }) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for _, sp := range st.spans {
+		br := bufio.NewReader(io.NewSectionReader(f, sp.off, sp.n))
+
+		for {
+			n, err := binary.ReadUvarint(br)
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+
+				return err
+			}
+
+			bs := make([]byte, n)
+			if _, err = io.ReadFull(br, bs); err != nil {
+				return err
+			}
+
+			var r stagedInsert
+			if err = json.Unmarshal(bs, &r); err != nil {
+				return err
+			}
+
+			if r.Prefix != bat.prefix {
+				if !flush() {
+					return nil
+				}
+				bat.prefix = r.Prefix
+			}
+
+			if r.Rows != nil && in.bulk.Load() {
+				if bulk == nil {
+					bulk = &bulkRows{
+						prefix:  r.Prefix,
+						schema:  r.Schema,
+						table:   r.Table,
+						columns: r.Columns,
+						target:  r.Target,
+					}
+				}
+
+				bulk.add(r.Rows, r.Values, r.Line)
+
+				if len(bulk.rows) >= in.bufSegCap*in.dbConnMax && !flush() {
+					return nil
+				}
+
+				continue
+			}
+
+			bat.add(r.Values, r.Line)
+
+			if len(bat.values) >= in.bufSegCap && !flush() {
+				return nil
+			}
+		}
+	}
+
+	flush()
+
+	return nil
+}
+
+// dependencies returns the tables that each given table depends on,
+// which are detected by the foreign keys of the destination and the declared hints,
+// each table depends on the previous table if the foreign keys are unknown,
+// so that the tables are loaded in sequence besides the hints.
+func (in *dst) dependencies(ctx context.Context, tables []string) map[string][]string {
+	r := make(map[string][]string, len(tables))
+
+	if len(tables) < 2 {
+		return r
+	}
+
+	if !in.fksLoaded {
+		in.loadForeignKeys(ctx)
+	}
+
+	names := make(map[string][]string, len(tables))
+	for _, t := range tables {
+		n := normalizeTable(t)
+		names[n] = append(names[n], t)
+	}
+
+	for i, t := range tables {
+		n := normalizeTable(t)
+
+		var rs []string
+		for d := range in.fks[n] {
+			rs = append(rs, d)
+		}
+
+		rs = append(rs, in.dependsOn[n]...)
+		sort.Strings(rs)
+
+		seen := map[string]struct{}{}
+
+		if in.fks == nil && i > 0 {
+			seen[tables[i-1]] = struct{}{}
+			r[t] = append(r[t], tables[i-1])
+		}
+
+		for _, d := range rs {
+			if d == n {
+				continue
+			}
+
+			for _, dt := range names[d] {
+				if _, exist := seen[dt]; exist {
+					continue
+				}
+				seen[dt] = struct{}{}
+				r[t] = append(r[t], dt)
+			}
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,148 @@
+package pipeline
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+func TestDestination_dependencies(t *testing.T) {
+	type input struct {
+		fks       map[string]map[string]struct{}
+		dependsOn map[string][]string
+		tables    []string
+	}
+
+	tc := []struct {
+		given    input
+		expected map[string][]string
+	}{
+		{
+			given: input{
+				fks: map[string]map[string]struct{}{
+					"orders": {"customers": {}, "orders": {}},
+					"items":  {"orders": {}, "products": {}},
+				},
+				dependsOn: map[string][]string{
+					"logs": {"items"},
+				},
+				tables: []string{"customers", "products", "orders", "`items`", "logs", "public.customers"},
+			},
+			expected: map[string][]string{
+				"orders":  {"customers", "public.customers"},
+				"`items`": {"orders", "products"},
+				"logs":    {"`items`"},
+			},
+		},
+		{
+			given: input{
+				dependsOn: map[string][]string{
+					"c": {"a"},
+				},
+				tables: []string{"a", "b", "c"},
+			},
+			expected: map[string][]string{
+				"b": {"a"},
+				"c": {"b", "a"},
+			},
+		},
+		{
+			given: input{
+				tables: []string{"a", "b", "c"},
+			},
+			expected: map[string][]string{
+				"b": {"a"},
+				"c": {"b"},
+			},
+		},
+	}
+
+	for i, c := range tc {
+		t.Run("case "+strconv.Itoa(i), func(t *testing.T) {
+			d := &dst{
+				fks:       c.given.fks,
+				fksLoaded: true,
+				dependsOn: c.given.dependsOn,
+			}
+
+			actual := d.dependencies(context.TODO(), c.given.tables)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestDestination_flushStagedBulk(t *testing.T) {
+	db := &fakeDB{}
+
+	in := newFakeDestination(sqlx.PostgresDialect, db)
+	in.parallel = true
+	in.bulk.Store(true)
+
+	ok, err := in.stageInsert(context.TODO(),
+		sqlx.Parse(sqlx.PostgresDialect, `INSERT INTO "Sales"."Customers" ("ID", Name) VALUES (1, 'a');`))
+	if !assert.NoError(t, err) || !assert.True(t, ok) {
+		return
+	}
+
+	err = in.flushStaged(context.TODO())
+	if assert.NoError(t, err) {
+		// Keep the quoting of the statement through staging.
+		assert.Contains(t, db.sqls(), `COPY "Sales"."Customers" ("ID", name) FROM STDIN`)
+	}
+}
+
+func TestDestination_flushStaged(t *testing.T) {
+	db := &fakeDB{}
+
+	in := newFakeDestination(sqlx.MySQLDialect, db)
+	in.parallel = true
+	// Orders and customers reference each other,
+	// and items references orders.
+	in.fks = map[string]map[string]struct{}{
+		"orders":    {"customers": {}},
+		"customers": {"orders": {}},
+		"items":     {"orders": {}},
+	}
+	in.fksLoaded = true
+
+	for _, s := range []string{
+		"INSERT INTO orders VALUES (1, 1);",
+		"INSERT INTO customers VALUES (1, 'Paul');",
+		"INSERT INTO orders VALUES (2, 1);",
+		"INSERT INTO items VALUES (1, 1), (2, 2);",
+		"INSERT INTO customers VALUES (2, 'Allen');",
+	} {
+		ok, err := in.stageInsert(context.TODO(), sqlx.Parse(sqlx.MySQLDialect, s))
+		if !assert.NoError(t, err) || !assert.True(t, ok) {
+			return
+		}
+	}
+
+	// The interleaved statements are staged in multiple spans.
+	if assert.NotNil(t, in.stg) {
+		assert.Equal(t, []string{"orders", "customers", "items"}, in.stg.order)
+		assert.Len(t, in.stg.tables["orders"].spans, 2)
+		assert.Len(t, in.stg.tables["customers"].spans, 2)
+		assert.Len(t, in.stg.tables["items"].spans, 1)
+	}
+
+	err := in.flushStaged(context.TODO())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Nil(t, in.stg)
+
+	// The cyclic dependencies are broken by loading the first staged table,
+	// the records of each table are loaded in staged order.
+	assert.Equal(t, []string{
+		"INSERT INTO `orders`  VALUES (1, 1), (2, 1)",
+		"INSERT INTO `customers`  VALUES (1, 'Paul'), (2, 'Allen')",
+		"INSERT INTO `items`  VALUES (1, 1), (2, 2)",
+	}, db.sqls())
+	assert.Equal(t, []string{"orders", "customers", "items"}, in.Touched())
+}
@@ -0,0 +1,162 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+const (
+	OnErrorAbort    = "abort"
+	OnErrorContinue = "continue"
+)
+
+// rejectSamples is the maximum number of Reject kept by Rejection.
+const rejectSamples = 10
+
+// Reject describes the statement rejected by the destination.
+type Reject struct {
+	// Line is the source line number of the statement, 0 if unknown.
+	Line int `json:"line"`
+	// SQL is the statement.
+	SQL string `json:"sql"`
+	// Error is the reason.
+	Error string `json:"error"`
+}
+
+// Rejection summarizes the rejected statements.
+type Rejection struct {
+	// Count is the total number of the rejected statements.
+	Count int
+	// Rejects are the first rejected statements.
+	Rejects []Reject
+}
+
+var errTooManyErrors = errors.New("too many errors")
+
+type rejector struct {
+	mu   sync.Mutex
+	max  int
+	rej  Rejection
+	file *os.File
+}
+
+// newRejector returns a rejector allows the given number of errors, 0 means no limit,
+// and writes the rejected statements into the given path if not blank.
+func newRejector(maxErrors int, path string) (*rejector, error) {
+	r := &rejector{max: maxErrors}
+
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create reject file: %w", err)
+		}
+		r.file = f
+	}
+
+	return r, nil
+}
+
+func (r *rejector) Close() error {
+	if r == nil || r.file == nil {
+		return nil
+	}
+
+	return r.file.Close()
+}
+
+// reject records the given statement, returns error if exceeds the budget.
+func (r *rejector) reject(ctx context.Context, line int, sql string, err error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rj := Reject{
+		Line:  line,
+		SQL:   sql,
+		Error: err.Error(),
+	}
+
+	tflog.Warn(ctx, "Rejected", map[string]any{
+		"line":  line,
+		"sql":   sqlx.RedactSQL(ctx, sql),
+		"error": rj.Error,
+	})
+
+	r.rej.Count++
+	if len(r.rej.Rejects) < rejectSamples {
+		r.rej.Rejects = append(r.rej.Rejects, rj)
+	}
+
+	if r.file != nil {
+		// Write in SQL format, which can be piped again after fixing.
+		var sb strings.Builder
+
+		sb.WriteString("-- Line ")
+		sb.WriteString(strconv.Itoa(line))
+		sb.WriteString(": ")
+		sb.WriteString(strings.Join(strings.Fields(rj.Error), " "))
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimRight(sql, "; \t\r\n"))
+		sb.WriteString(";\n")
+
+		if _, werr := r.file.WriteString(sb.String()); werr != nil {
+			return fmt.Errorf("cannot write reject file: %w", werr)
+		}
+	}
+
+	if r.max > 0 && r.rej.Count > r.max {
+		return fmt.Errorf("%w: rejected %d statements, exceeds max errors %d: %v",
+			errTooManyErrors, r.rej.Count, r.max, err)
+	}
+
+	return nil
+}
+
+func (in *dst) Rejection() Rejection {
+	if in.rej == nil {
+		return Rejection{}
+	}
+
+	in.rej.mu.Lock()
+	defer in.rej.mu.Unlock()
+
+	return Rejection{
+		Count:   in.rej.rej.Count,
+		Rejects: append([]Reject(nil), in.rej.rej.Rejects...),
+	}
+}
+
+// tolerate rejects the given failed statement and returns nil if continuing on error,
+// otherwise returns the given error.
+func (in *dst) tolerate(ctx context.Context, line int, sql string, err error) error {
+	// A failed statement aborts the whole transaction on Postgres.
+	if err != nil && in.sentryTx && in.drv == sqlx.PostgresDialect {
+		return err
+	}
+
+	return in.skip(ctx, line, sql, err)
+}
+
+// skip is similar to tolerate,
+// but the caller must ensure the failed statement does not abort the transaction.
+func (in *dst) skip(ctx context.Context, line int, sql string, err error) error {
+	if err == nil || in.rej == nil || ctx.Err() != nil || errors.Is(err, errTooManyErrors) {
+		return err
+	}
+
+	var te *TupleError
+	if errors.As(err, &te) {
+		// Record the driver error only.
+		err = te.Err
+	}
+
+	return in.rej.reject(ctx, line, sql, err)
+}
@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejector_reject(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "rejects.sql")
+
+	r, err := newRejector(rejectSamples+1, p)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ctx := context.TODO()
+
+	err = r.reject(ctx, 3, "INSERT INTO t VALUES (1);", errors.New("duplicate\nkey"))
+	assert.NoError(t, err)
+
+	for i := 0; i < rejectSamples; i++ {
+		err = r.reject(ctx, 0, "INSERT INTO t VALUES ("+strconv.Itoa(i)+")", errors.New("bad"))
+		assert.NoError(t, err)
+	}
+
+	err = r.reject(ctx, 42, "DELETE FROM t", errors.New("locked"))
+	assert.ErrorIs(t, err, errTooManyErrors)
+
+	assert.Equal(t, rejectSamples+2, r.rej.Count)
+	assert.Len(t, r.rej.Rejects, rejectSamples)
+	assert.Equal(t, Reject{Line: 3, SQL: "INSERT INTO t VALUES (1);", Error: "duplicate\nkey"}, r.rej.Rejects[0])
+
+	assert.NoError(t, r.Close())
+
+	bs, err := os.ReadFile(p)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	actual := string(bs)
+	assert.Contains(t, actual, "-- Line 3: duplicate key\nINSERT INTO t VALUES (1);\n")
+	assert.Contains(t, actual, "-- Line 42: locked\nDELETE FROM t;\n")
+}
@@ -0,0 +1,173 @@
+package pipeline
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats summarizes the execution of Destination.
+type Stats struct {
+	// Statements counts the executed statements by type, i.e. ddl, dml, tcl and dcl.
+	Statements map[string]int
+	// InsertRows counts the inserted rows by table.
+	InsertRows map[string]int
+	// InsertDurations accumulates the durations of inserting by table.
+	InsertDurations map[string]time.Duration
+	// Batches counts the flushed insert batches and bulk loads.
+	Batches int
+	// Ignored counts the ignored statements,
+	// i.e. the unknown statements and the transaction control statements inside Atomic.
+	Ignored int
+	// IgnoredStatements records the first ignored statements.
+	IgnoredStatements []string
+	// Skipped counts the statements applied before resuming from the checkpoint.
+	Skipped int
+	// Retries counts the retrying of the transient errors.
+	Retries int
+	// PeakBufferRows is the maximum number of the rows buffered in memory.
+	PeakBufferRows int
+	// BulkDisabled is the error stopping the bulk loading, blank if not stopped.
+	BulkDisabled string
+}
+
+// stats counts the executions of dst,
+// which is safe for concurrent use.
+type stats struct {
+	mu         sync.Mutex
+	statements map[string]int
+	rows       map[string]int
+	durations  map[string]time.Duration
+	batches    int
+	ignored    []string
+	ignoredN   int
+	skipped    int
+	peakRows   int
+	bulkOff    string
+
+	retries atomic.Int64
+}
+
+func newStats() *stats {
+	return &stats{
+		statements: map[string]int{
+			StatementDDL: 0,
+			StatementDML: 0,
+			StatementTCL: 0,
+			StatementDCL: 0,
+		},
+		rows:      map[string]int{},
+		durations: map[string]time.Duration{},
+	}
+}
+
+func (s *stats) statement(typ string) {
+	s.mu.Lock()
+	s.statements[typ]++
+	s.mu.Unlock()
+}
+
+func (s *stats) ignore(sql string) {
+	s.mu.Lock()
+	s.ignoredN++
+	if len(s.ignored) < rejectSamples {
+		s.ignored = append(s.ignored, sql)
+	}
+	s.mu.Unlock()
+}
+
+func (s *stats) skip() {
+	s.mu.Lock()
+	s.skipped++
+	s.mu.Unlock()
+}
+
+// insert counts the inserted rows of the given table.
+func (s *stats) insert(tbl string, n int) {
+	s.mu.Lock()
+	s.rows[tbl] += n
+	s.mu.Unlock()
+}
+
+// elapse accumulates the inserting duration of the given table since the given time.
+func (s *stats) elapse(tbl string, since time.Time) {
+	d := time.Since(since)
+
+	s.mu.Lock()
+	s.durations[tbl] += d
+	s.mu.Unlock()
+}
+
+// disableBulk records the error stopping the bulk loading.
+func (s *stats) disableBulk(err error) {
+	s.mu.Lock()
+	s.bulkOff = err.Error()
+	s.mu.Unlock()
+}
+
+func (s *stats) batch() {
+	s.mu.Lock()
+	s.batches++
+	s.mu.Unlock()
+}
+
+// buffer records the given number of the buffered rows.
+func (s *stats) buffer(n int) {
+	s.mu.Lock()
+	if n > s.peakRows {
+		s.peakRows = n
+	}
+	s.mu.Unlock()
+}
+
+// snapshot returns a copy of the inserted rows,
+// which is restored if the rows are rolled back.
+func (s *stats) snapshot() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := make(map[string]int, len(s.rows))
+	for t, n := range s.rows {
+		rows[t] = n
+	}
+
+	return rows
+}
+
+func (s *stats) restore(rows map[string]int) {
+	s.mu.Lock()
+	s.rows = rows
+	s.mu.Unlock()
+}
+
+func (in *dst) Stats() Stats {
+	in.stats.mu.Lock()
+	defer in.stats.mu.Unlock()
+
+	st := Stats{
+		Statements:        make(map[string]int, len(in.stats.statements)),
+		InsertRows:        make(map[string]int, len(in.stats.rows)),
+		InsertDurations:   make(map[string]time.Duration, len(in.stats.durations)),
+		Batches:           in.stats.batches,
+		Ignored:           in.stats.ignoredN,
+		IgnoredStatements: append([]string(nil), in.stats.ignored...),
+		Skipped:           in.stats.skipped,
+		Retries:           int(in.stats.retries.Load()),
+		PeakBufferRows:    in.stats.peakRows,
+		BulkDisabled:      in.stats.bulkOff,
+	}
+
+	for t, n := range in.stats.statements {
+		st.Statements[t] = n
+	}
+
+	for t, n := range in.stats.rows {
+		st.InsertRows[t] = n
+	}
+
+	for t, d := range in.stats.durations {
+		st.InsertDurations[t] = d
+	}
+
+	return st
+}
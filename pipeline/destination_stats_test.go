@@ -0,0 +1,53 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+func TestDestination_countInsert(t *testing.T) {
+	tc := []struct {
+		given    string
+		affected int64
+		expected map[string]int
+	}{
+		{
+			given:    "INSERT INTO t SELECT * FROM s",
+			affected: 3,
+			expected: map[string]int{"t": 3},
+		},
+		{
+			given:    "UPDATE t SET a = 1",
+			affected: 3,
+			expected: map[string]int{},
+		},
+		{
+			given:    "INSERT INTO t SELECT * FROM s WHERE 1 = 0",
+			affected: 0,
+			expected: map[string]int{},
+		},
+		{
+			given:    "INSERT INTO t SELECT * FROM s",
+			affected: -1,
+			expected: map[string]int{},
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.given, func(t *testing.T) {
+			d := &dst{drv: sqlx.MySQLDialect, stats: newStats()}
+
+			err := d.countInsert(context.TODO(), sqlx.Parse(d.drv, c.given),
+				func(ctx context.Context) (int64, error) {
+					return c.affected, nil
+				})
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, d.stats.snapshot())
+			}
+		})
+	}
+}
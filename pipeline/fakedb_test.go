@@ -0,0 +1,192 @@
+package pipeline
+
+import (
+	"context"
+	stdsql "database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+)
+
+// fakeDB is the fake database recording the executed sql of each session,
+// the sql is failed if fail returns error,
+// and the query returns the rows of query if not nil.
+type fakeDB struct {
+	mu    sync.Mutex
+	conns int
+	execs []fakeExec
+
+	fail  func(sql string) error
+	query func(sql string, args []driver.NamedValue) ([]string, [][]driver.Value, error)
+}
+
+// fakeExec is the sql executed in the session of conn.
+type fakeExec struct {
+	conn int
+	sql  string
+}
+
+// sqls returns the executed sql in order.
+func (db *fakeDB) sqls() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	r := make([]string, 0, len(db.execs))
+	for _, e := range db.execs {
+		r = append(r, e.sql)
+	}
+
+	return r
+}
+
+func (db *fakeDB) record(conn int, sql string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.execs = append(db.execs, fakeExec{conn: conn, sql: sql})
+
+	if db.fail != nil {
+		return db.fail(sql)
+	}
+
+	return nil
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.conns++
+
+	return &fakeConn{db: db, id: db.conns}, nil
+}
+
+func (db *fakeDB) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+	id int
+}
+
+func (c *fakeConn) Prepare(sql string) (driver.Stmt, error) {
+	if err := c.db.record(c.id, sql); err != nil {
+		return nil, err
+	}
+
+	return fakeStmt{}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	if err := c.db.record(c.id, "BEGIN"); err != nil {
+		return nil, err
+	}
+
+	return fakeTx{c: c}, nil
+}
+
+func (c *fakeConn) Ping(context.Context) error {
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, sql string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := c.db.record(c.id, sql); err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, sql string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.db.record(c.id, sql); err != nil {
+		return nil, err
+	}
+
+	if c.db.query == nil {
+		return &fakeRows{}, nil
+	}
+
+	cols, vals, err := c.db.query(sql, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeRows{cols: cols, vals: vals}, nil
+}
+
+type fakeTx struct {
+	c *fakeConn
+}
+
+func (t fakeTx) Commit() error {
+	return t.c.db.record(t.c.id, "COMMIT")
+}
+
+func (t fakeTx) Rollback() error {
+	return t.c.db.record(t.c.id, "ROLLBACK")
+}
+
+// fakeStmt is the prepared statement accepting any arguments.
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error {
+	return nil
+}
+
+func (fakeStmt) NumInput() int {
+	return -1
+}
+
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct {
+	cols []string
+	vals [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.cols
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.vals) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.vals[0])
+	r.vals = r.vals[1:]
+
+	return nil
+}
+
+// newFakeDestination returns the destination of the given driver on the given fake database.
+func newFakeDestination(drv string, db *fakeDB) *dst {
+	return &dst{
+		drv:       drv,
+		db:        stdsql.OpenDB(db),
+		dbConnMax: 1,
+		buf:       map[string][]batch{},
+		bufSegCap: 100,
+		bufTables: map[string]string{},
+		bulkBuf:   map[string]*bulkRows{},
+		relaxed:   map[string][]string{},
+		touched:   map[string]struct{}{},
+		created:   map[string]struct{}{},
+		stats:     newStats(),
+	}
+}
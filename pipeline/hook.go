@@ -0,0 +1,144 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+	"github.com/seal-io/terraform-provider-byteset/utils/strx"
+)
+
+// NewHook returns the Source of the given hook,
+// which is a local/remote SQL file address or SQL statements,
+// the statements of hook are executed verbatim even if cannot be classified,
+// like REFRESH MATERIALIZED VIEW or VACUUM.
+func NewHook(ctx context.Context, hook string) (Source, error) {
+	for _, s := range []string{"file://", "http://", "https://", "raw://", "raw+base64://"} {
+		if strings.HasPrefix(hook, s) {
+			src, err := NewSource(ctx, hook, 1, sqlx.Wait{})
+			if err != nil {
+				return nil, err
+			}
+
+			return srcHook{Source: src}, nil
+		}
+	}
+
+	return srcHook{
+		Source: &srcFile{
+			f:    io.NopCloser(strings.NewReader(hook)),
+			dg:   strx.Sum(hook),
+			size: int64(len(hook)),
+		},
+	}, nil
+}
+
+type verbatimKey struct{}
+
+// isVerbatim returns true if the piping statement must be executed verbatim.
+func isVerbatim(ctx context.Context) bool {
+	v, _ := ctx.Value(verbatimKey{}).(bool)
+	return v
+}
+
+// srcHook pipes the statements to execute verbatim.
+type srcHook struct {
+	Source
+}
+
+func (in srcHook) Pipe(ctx context.Context, dst Receiver) error {
+	return in.Source.Pipe(ctx, verbatim{dst})
+}
+
+// verbatim wraps the Receiver to execute the statements verbatim.
+type verbatim struct {
+	Receiver
+}
+
+func (v verbatim) Exec(ctx context.Context, sql string) error {
+	return v.Receiver.Exec(context.WithValue(ctx, verbatimKey{}, true), sql)
+}
+
+// Chain returns the Source to pipe the given sources in order,
+// the receiver is completed after all sources are piped.
+func Chain(srcs ...Source) Source {
+	if len(srcs) == 1 {
+		return srcs[0]
+	}
+
+	return srcChain(srcs)
+}
+
+type srcChain []Source
+
+func (in srcChain) Close() error {
+	var err error
+
+	for i := range in {
+		if cerr := in[i].Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+func (in srcChain) Pipe(ctx context.Context, dst Receiver) error {
+	for i := range in {
+		err := in[i].Pipe(ctx, incomplete{dst})
+		if err != nil {
+			return err
+		}
+	}
+
+	return dst.Complete(ctx)
+}
+
+func (in srcChain) Digest() (string, error) {
+	dgs := make([]string, 0, len(in))
+
+	for i := range in {
+		dg, err := in[i].Digest()
+		if err != nil {
+			return "", err
+		}
+
+		dgs = append(dgs, dg)
+	}
+
+	return strx.Sum(dgs...), nil
+}
+
+func (in srcChain) BytesRead() int64 {
+	var n int64
+	for i := range in {
+		n += in[i].BytesRead()
+	}
+
+	return n
+}
+
+func (in srcChain) Size() int64 {
+	var n int64
+
+	for i := range in {
+		s := in[i].Size()
+		if s < 0 {
+			return -1
+		}
+
+		n += s
+	}
+
+	return n
+}
+
+// incomplete wraps the Receiver to ignore the completion.
+type incomplete struct {
+	Receiver
+}
+
+func (incomplete) Complete(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// sqlRecorder records the piped sql and the completion.
+type sqlRecorder struct {
+	sqls      []string
+	verbatims []bool
+	completed int
+}
+
+func (in *sqlRecorder) Exec(ctx context.Context, sql string) error {
+	in.sqls = append(in.sqls, sql)
+	in.verbatims = append(in.verbatims, isVerbatim(ctx))
+
+	return nil
+}
+
+func (in *sqlRecorder) Complete(ctx context.Context) error {
+	in.completed++
+	return nil
+}
+
+func TestChain(t *testing.T) {
+	var srcs []Source
+
+	for _, h := range []string{
+		"CREATE SCHEMA s;",
+		"raw://INSERT INTO s.t VALUES (1);\nINSERT INTO s.t VALUES (2);",
+		"ANALYZE s.t;",
+	} {
+		src, err := NewHook(context.TODO(), h)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		srcs = append(srcs, src)
+	}
+
+	body, err := NewSource(context.TODO(), "raw://VACUUM s.t;", 1, sqlx.Wait{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	srcs = append(srcs[:2], body, srcs[2])
+
+	src := Chain(srcs...)
+	defer func() { _ = src.Close() }()
+
+	actual := &sqlRecorder{}
+
+	err = src.Pipe(context.TODO(), actual)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			"CREATE SCHEMA s;",
+			"INSERT INTO s.t VALUES (1);",
+			"INSERT INTO s.t VALUES (2);",
+			"VACUUM s.t;",
+			"ANALYZE s.t;",
+		}, actual.sqls)
+		assert.Equal(t, []bool{true, true, true, false, true}, actual.verbatims)
+		assert.Equal(t, 1, actual.completed)
+		assert.Equal(t, src.Size(), src.BytesRead())
+	}
+}
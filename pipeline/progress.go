@@ -0,0 +1,197 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type ProgressOptions struct {
+	// Interval specifies the interval to report the progress,
+	// disables the periodic reporting if not positive.
+	Interval time.Duration
+	// Path specifies the local file to write the progress in JSON lines,
+	// the final progress is written even if the periodic reporting is disabled,
+	// disables writing if blank.
+	Path string
+}
+
+// Progress describes the progress of piping.
+type Progress struct {
+	// Time is the reporting time.
+	Time time.Time `json:"time"`
+	// BytesRead is the number of the bytes read from Source.
+	BytesRead int64 `json:"bytes_read"`
+	// BytesTotal is the total number of the bytes of Source, -1 if unknown.
+	BytesTotal int64 `json:"bytes_total"`
+	// Statements is the number of the executed statements.
+	Statements int `json:"statements"`
+	// Rows is the number of the inserted rows.
+	Rows int `json:"rows"`
+	// StatementsPerSecond is the average executing rate of the statements.
+	StatementsPerSecond float64 `json:"statements_per_second"`
+	// RowsPerSecond is the average inserting rate of the rows.
+	RowsPerSecond float64 `json:"rows_per_second"`
+	// ETA is the estimated remaining duration, blank if unknown.
+	ETA string `json:"eta,omitempty"`
+	// Done is true if the piping is completed.
+	Done bool `json:"done"`
+}
+
+// Pipe streams the given source into the given destination,
+// and reports the progress periodically.
+func Pipe(ctx context.Context, src Source, dst Destination, opts ProgressOptions) error {
+	if opts.Interval <= 0 && opts.Path == "" {
+		return src.Pipe(ctx, dst)
+	}
+
+	p, err := newProgressor(src, dst, opts.Path)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = p.Close() }()
+
+	var (
+		stop = make(chan struct{})
+		done = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+
+		// Report the final progress only.
+		if opts.Interval <= 0 {
+			return
+		}
+
+		t := time.NewTicker(opts.Interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				p.report(ctx, false)
+			}
+		}
+	}()
+
+	err = src.Pipe(ctx, dst)
+
+	close(stop)
+	<-done
+
+	if err != nil {
+		return err
+	}
+
+	p.report(ctx, true)
+
+	return nil
+}
+
+type progressor struct {
+	src   Source
+	dst   Destination
+	start time.Time
+	file  *os.File
+}
+
+func newProgressor(src Source, dst Destination, path string) (*progressor, error) {
+	p := &progressor{
+		src:   src,
+		dst:   dst,
+		start: time.Now(),
+	}
+
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create progress file: %w", err)
+		}
+		p.file = f
+	}
+
+	return p, nil
+}
+
+func (p *progressor) Close() error {
+	if p.file == nil {
+		return nil
+	}
+
+	return p.file.Close()
+}
+
+// report logs the current progress and writes it into the progress file if found.
+func (p *progressor) report(ctx context.Context, done bool) {
+	pg := p.progress(time.Now())
+	pg.Done = done
+
+	tflog.Info(ctx, "Progress", map[string]any{
+		"bytes_read":            pg.BytesRead,
+		"bytes_total":           pg.BytesTotal,
+		"statements":            pg.Statements,
+		"rows":                  pg.Rows,
+		"statements_per_second": pg.StatementsPerSecond,
+		"rows_per_second":       pg.RowsPerSecond,
+		"eta":                   pg.ETA,
+		"done":                  pg.Done,
+	})
+
+	if p.file == nil {
+		return
+	}
+
+	bs, err := json.Marshal(pg)
+	if err != nil {
+		return
+	}
+
+	if _, err = p.file.Write(append(bs, '\n')); err != nil {
+		tflog.Warn(ctx, "Cannot write progress file", map[string]any{"error": err.Error()})
+	}
+}
+
+// progress returns the progress at the given time.
+func (p *progressor) progress(now time.Time) Progress {
+	st := p.dst.Stats()
+
+	pg := Progress{
+		Time:       now,
+		BytesRead:  p.src.BytesRead(),
+		BytesTotal: p.src.Size(),
+	}
+
+	for _, n := range st.Statements {
+		pg.Statements += n
+	}
+
+	for _, n := range st.InsertRows {
+		pg.Rows += n
+	}
+
+	elapsed := now.Sub(p.start)
+	if elapsed <= 0 {
+		return pg
+	}
+
+	pg.StatementsPerSecond = float64(pg.Statements) / elapsed.Seconds()
+	pg.RowsPerSecond = float64(pg.Rows) / elapsed.Seconds()
+
+	// Estimate by the reading rate.
+	if pg.BytesTotal > 0 && pg.BytesRead > 0 && pg.BytesRead <= pg.BytesTotal {
+		eta := time.Duration(float64(elapsed) * float64(pg.BytesTotal-pg.BytesRead) / float64(pg.BytesRead))
+		pg.ETA = eta.Round(time.Second).String()
+	}
+
+	return pg
+}
@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressor_progress(t *testing.T) {
+	src := &srcFile{f: io.NopCloser(strings.NewReader("")), size: 400}
+	src.n.Store(100)
+
+	d := &dst{stats: newStats()}
+	d.stats.statement(StatementDDL)
+	d.stats.statement(StatementDML)
+	d.stats.statement(StatementDML)
+	d.stats.insert("t1", 10)
+	d.stats.insert("t2", 30)
+
+	start := time.Now()
+	p := &progressor{src: src, dst: d, start: start}
+
+	actual := p.progress(start.Add(2 * time.Second))
+	expected := Progress{
+		Time:                start.Add(2 * time.Second),
+		BytesRead:           100,
+		BytesTotal:          400,
+		Statements:          3,
+		Rows:                40,
+		StatementsPerSecond: 1.5,
+		RowsPerSecond:       20,
+		ETA:                 "6s",
+	}
+	assert.Equal(t, expected, actual)
+
+	// Unknown total.
+	src.size = -1
+
+	actual = p.progress(start.Add(2 * time.Second))
+	assert.Equal(t, "", actual.ETA)
+}
+
+func TestPipe_finalProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.jsonl")
+
+	src := &srcFile{f: io.NopCloser(strings.NewReader("")), size: 0}
+	d := &dst{stats: newStats()}
+
+	// Pipe nothing but the final progress.
+	err := Pipe(context.TODO(), src, d, ProgressOptions{Path: path})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	bs, err := os.ReadFile(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(bs)), "\n")
+	if assert.Len(t, lines, 1) {
+		var pg Progress
+		if assert.NoError(t, json.Unmarshal([]byte(lines[0]), &pg)) {
+			assert.True(t, pg.Done)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// Report describes an execution of piping, which is written in JSON.
+type Report struct {
+	// StartedAt is the starting time of the execution.
+	StartedAt time.Time `json:"started_at"`
+	// EndedAt is the ending time of the execution.
+	EndedAt time.Time `json:"ended_at"`
+	// Source is the redacted address of Source.
+	Source string `json:"source"`
+	// Destination is the redacted address of Destination.
+	Destination string `json:"destination"`
+	// Digest is the digest of Source, blank if unknown.
+	Digest string `json:"digest,omitempty"`
+	// Stats is the statistics of the execution.
+	Stats ReportStats `json:"stats"`
+	// Tables are the tables created or written by the execution,
+	// in order of their first appearance.
+	Tables []ReportTable `json:"tables"`
+	// Rejected is the rejected statements if continuing on error.
+	Rejected ReportRejected `json:"rejected"`
+	// Ignored are the first ignored statements.
+	Ignored []string `json:"ignored"`
+	// Error is the error of the execution, blank if succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+type ReportStats struct {
+	Statements     map[string]int `json:"statements"`
+	Batches        int            `json:"batches"`
+	BytesRead      int64          `json:"bytes_read"`
+	Ignored        int            `json:"ignored"`
+	Skipped        int            `json:"skipped"`
+	Retries        int            `json:"retries"`
+	PeakBufferRows int            `json:"peak_buffer_rows"`
+}
+
+type ReportTable struct {
+	Name       string `json:"name"`
+	InsertRows int    `json:"insert_rows"`
+	// InsertDuration is the accumulated duration of inserting in form of Go duration,
+	// which may exceed the execution duration if inserting in parallel.
+	InsertDuration string `json:"insert_duration"`
+}
+
+type ReportRejected struct {
+	Count      int      `json:"count"`
+	Statements []Reject `json:"statements"`
+}
+
+// NewReport returns the Report of the execution from the given source address to the given destination address,
+// which is started at the given time and ended with the given error.
+func NewReport(
+	src Source,
+	srcAddr string,
+	dst Destination,
+	dstAddr string,
+	startedAt time.Time,
+	err error,
+) Report {
+	st := dst.Stats()
+	rej := dst.Rejection()
+
+	r := Report{
+		StartedAt:   startedAt,
+		EndedAt:     time.Now(),
+		Source:      RedactAddress(srcAddr),
+		Destination: sqlx.RedactAddress(dstAddr),
+		Stats: ReportStats{
+			Statements:     st.Statements,
+			Batches:        st.Batches,
+			BytesRead:      src.BytesRead(),
+			Ignored:        st.Ignored,
+			Skipped:        st.Skipped,
+			Retries:        st.Retries,
+			PeakBufferRows: st.PeakBufferRows,
+		},
+		Tables: make([]ReportTable, 0, len(dst.Touched())),
+		Rejected: ReportRejected{
+			Count:      rej.Count,
+			Statements: rej.Rejects,
+		},
+		Ignored: st.IgnoredStatements,
+	}
+
+	if dg, derr := src.Digest(); derr == nil {
+		r.Digest = dg
+	}
+
+	for _, t := range dst.Touched() {
+		r.Tables = append(r.Tables, ReportTable{
+			Name:           t,
+			InsertRows:     st.InsertRows[t],
+			InsertDuration: st.InsertDurations[t].String(),
+		})
+	}
+
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	return r
+}
+
+// WriteReport writes the given report into the given local file in JSON.
+func WriteReport(path string, r Report) error {
+	bs, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal report: %w", err)
+	}
+
+	err = os.WriteFile(path, append(bs, '\n'), 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot write report: %w", err)
+	}
+
+	return nil
+}
+
+// RedactAddress returns the given source address with the secrets masked,
+// the content of the raw address is omitted.
+func RedactAddress(addr string) string {
+	for _, s := range []string{"raw://", "raw+base64://"} {
+		if strings.HasPrefix(addr, s) {
+			return s + "..."
+		}
+	}
+
+	return sqlx.RedactAddress(addr)
+}
@@ -4,12 +4,13 @@ import (
 	"bufio"
 	"context"
 	stdsql "database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
-	"time"
+	"sync/atomic"
 
 	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
 	"github.com/seal-io/terraform-provider-byteset/utils/strx"
@@ -18,32 +19,88 @@ import (
 type Source interface {
 	io.Closer
 
-	Pipe(ctx context.Context, destination Destination) error
+	// Pipe streams the dataset into the given receiver.
+	Pipe(ctx context.Context, receiver Receiver) error
+
+	// Digest returns the digest of the dataset.
+	Digest() (string, error)
+
+	// BytesRead returns the number of the bytes read by piping.
+	BytesRead() int64
+
+	// Size returns the total number of the bytes of the dataset,
+	// returns -1 if unknown.
+	Size() int64
 }
 
-func NewSource(ctx context.Context, addr string, addrConnMax int) (Source, error) {
+type lineKey struct{}
+
+// WithLine returns the context carrying the source line number of the piping statement.
+func WithLine(ctx context.Context, line int) context.Context {
+	return context.WithValue(ctx, lineKey{}, line)
+}
+
+// LineFrom returns the source line number of the piping statement,
+// returns 0 if unknown.
+func LineFrom(ctx context.Context) int {
+	line, _ := ctx.Value(lineKey{}).(int)
+	return line
+}
+
+// NewSource returns the Source of the given address,
+// the given w specifies how to wait for the database to be ready if the address is a database.
+func NewSource(ctx context.Context, addr string, addrConnMax int, w sqlx.Wait) (Source, error) {
 	switch {
 	case strings.HasPrefix(addr, "file://"):
 		addr = strings.TrimPrefix(addr, "file://")
 
 		local, err := os.Open(addr)
 		if err != nil {
-			return nil, fmt.Errorf("cannot open local file from %q: %w", addr, err)
+			return nil, fmt.Errorf("cannot open local file from %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
 		}
 
-		return &srcFile{f: local}, nil
+		fi, err := local.Stat()
+		if err != nil {
+			_ = local.Close()
+			return nil, fmt.Errorf("cannot stat local file from %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+		}
+
+		return &srcFile{f: local, size: fi.Size()}, nil
 
 	case strings.HasPrefix(addr, "http://") || strings.HasPrefix(addr, "https://"):
 		remote, err := http.Get(addr)
 		if err != nil {
-			return nil, fmt.Errorf("cannot open remote file from %q: %w", addr, err)
+			return nil, fmt.Errorf("cannot open remote file from %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+		}
+
+		etag, lastModified := remote.Header.Get("ETag"), remote.Header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			return &srcFile{
+				f:    remote.Body,
+				size: remote.ContentLength,
+				// Trust the validators of the remote file.
+				dg: strx.Sum(addr, etag, lastModified),
+			}, nil
 		}
 
-		return &srcFile{f: remote.Body}, nil
+		// Spool the remote file without validators,
+		// so that the digest can be calculated from the content.
+		spool, err := spoolFile(remote.Body)
+		if err != nil {
+			return nil, fmt.Errorf("cannot spool remote file from %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+		}
+
+		fi, err := spool.Stat()
+		if err != nil {
+			_ = spool.Close()
+			return nil, fmt.Errorf("cannot stat remote file from %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+		}
+
+		return &srcFile{f: spool, size: fi.Size()}, nil
 
 	case strings.HasPrefix(addr, "raw://"):
 		raw := addr[len("raw://"):]
-		return &srcFile{f: io.NopCloser(strings.NewReader(raw))}, nil
+		return &srcFile{f: io.NopCloser(strings.NewReader(raw)), dg: strx.Sum(raw), size: int64(len(raw))}, nil
 
 	case strings.HasPrefix(addr, "raw+base64://"):
 		raw, err := strx.DecodeBase64(addr[len("raw+base64://"):])
@@ -51,24 +108,24 @@ func NewSource(ctx context.Context, addr string, addrConnMax int) (Source, error
 			return nil, fmt.Errorf("cannot decode raw base64 content: %w", err)
 		}
 
-		return &srcFile{f: io.NopCloser(strings.NewReader(raw))}, nil
+		return &srcFile{f: io.NopCloser(strings.NewReader(raw)), dg: strx.Sum(raw), size: int64(len(raw))}, nil
 
 	default:
 	}
 
+	ctx = sqlx.MaskSecrets(ctx, addr)
+
 	// Load database.
 	drv, db, err := sqlx.LoadDatabase(addr, addrConnMax)
 	if err != nil {
-		return nil, fmt.Errorf("cannot load database from %q: %w", addr, err)
+		return nil, fmt.Errorf("cannot load database from %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
 	}
 
 	// Detect connectivity.
-	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
-	defer cancel()
-
-	err = sqlx.IsDatabaseConnected(ctx, db)
+	err = sqlx.IsDatabaseConnected(ctx, db, w)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect database on %q: %w", addr, err)
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot connect database on %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
 	}
 
 	return &srcDatabase{
@@ -78,25 +135,114 @@ func NewSource(ctx context.Context, addr string, addrConnMax int) (Source, error
 }
 
 type srcFile struct {
-	f io.ReadCloser
+	f    io.ReadCloser
+	dg   string
+	size int64
+	n    atomic.Int64
 }
 
 func (in *srcFile) Close() error {
 	return in.f.Close()
 }
 
-func (in *srcFile) Pipe(ctx context.Context, dst Destination) error {
-	ss := bufio.NewScanner(in.f)
-	ss.Split(split)
+func (in *srcFile) Pipe(ctx context.Context, dst Receiver) error {
+	lc := &lineCounter{next: 1}
+	ss := bufio.NewScanner(&countingReader{r: in.f, n: &in.n})
+	ss.Split(lc.split)
 
 	for ss.Scan() {
-		err := dst.Exec(ctx, ss.Text())
+		err := dst.Exec(WithLine(ctx, lc.line), ss.Text())
 		if err != nil {
 			return err
 		}
 	}
 
-	return dst.Flush(ctx)
+	return dst.Complete(ctx)
+}
+
+func (in *srcFile) Digest() (string, error) {
+	if in.dg != "" {
+		return in.dg, nil
+	}
+
+	// Calculate from the content,
+	// and then rewind for piping.
+	rs, ok := in.f.(io.ReadSeeker)
+	if !ok {
+		return "", errors.New("cannot calculate digest from unseekable file")
+	}
+
+	dg, err := strx.SumReader(rs)
+	if err != nil {
+		return "", fmt.Errorf("cannot calculate digest: %w", err)
+	}
+
+	_, err = rs.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", fmt.Errorf("cannot rewind file: %w", err)
+	}
+
+	in.dg = dg
+
+	return in.dg, nil
+}
+
+func (in *srcFile) BytesRead() int64 {
+	return in.n.Load()
+}
+
+func (in *srcFile) Size() int64 {
+	return in.size
+}
+
+// spooledFile is a temporary file removed once closed.
+type spooledFile struct {
+	*os.File
+}
+
+func (in spooledFile) Close() error {
+	err := in.File.Close()
+	_ = os.Remove(in.Name())
+
+	return err
+}
+
+// spoolFile copies the given reader into a temporary file and rewinds it,
+// the given reader is closed.
+func spoolFile(r io.ReadCloser) (spooledFile, error) {
+	defer func() { _ = r.Close() }()
+
+	f, err := os.CreateTemp("", "byteset-spool-*")
+	if err != nil {
+		return spooledFile{}, fmt.Errorf("cannot create temporary file: %w", err)
+	}
+
+	spool := spooledFile{File: f}
+
+	_, err = io.Copy(spool, r)
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+
+	if err != nil {
+		_ = spool.Close()
+		return spooledFile{}, fmt.Errorf("cannot write temporary file: %w", err)
+	}
+
+	return spool, nil
+}
+
+// countingReader counts the bytes read from the underlay reader.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (in *countingReader) Read(p []byte) (int, error) {
+	n, err := in.r.Read(p)
+	in.n.Add(int64(n))
+
+	return n, err
 }
 
 type srcDatabase struct {
@@ -108,6 +254,18 @@ func (in *srcDatabase) Close() error {
 	return in.db.Close()
 }
 
-func (in *srcDatabase) Pipe(ctx context.Context, dst Destination) error {
+func (in *srcDatabase) Pipe(ctx context.Context, dst Receiver) error {
 	return nil
 }
+
+func (in *srcDatabase) Digest() (string, error) {
+	return "", errors.New("cannot calculate digest from database")
+}
+
+func (in *srcDatabase) BytesRead() int64 {
+	return 0
+}
+
+func (in *srcDatabase) Size() int64 {
+	return -1
+}
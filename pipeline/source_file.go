@@ -75,6 +75,22 @@ func split(data []byte, atEOF bool) (int, []byte, error) {
 	return 0, nil, nil
 }
 
+// lineCounter wraps the split to record the starting line number of the latest token.
+type lineCounter struct {
+	line int
+	next int
+}
+
+func (in *lineCounter) split(data []byte, atEOF bool) (int, []byte, error) {
+	adv, tkn, err := split(data, atEOF)
+	if adv > 0 {
+		in.line = in.next + bytes.Count(data[:len(data)-len(dropStartCRLF(data))], []byte{'\n'})
+		in.next += bytes.Count(data[:adv], []byte{'\n'})
+	}
+
+	return adv, tkn, err
+}
+
 // dropStartCRLF drops leading \r\n from the data.
 func dropStartCRLF(data []byte) []byte {
 	return bytes.TrimLeft(data, "\r\n")
@@ -2,10 +2,15 @@ package pipeline
 
 import (
 	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
 	"github.com/seal-io/terraform-provider-byteset/utils/testx"
 )
 
@@ -78,3 +83,103 @@ VALUES ('Test 1');`,
 
 	assert.Equal(t, expected, actual)
 }
+
+type lineRecorder struct {
+	lines []int
+}
+
+func (in *lineRecorder) Exec(ctx context.Context, sql string) error {
+	in.lines = append(in.lines, LineFrom(ctx))
+	return nil
+}
+
+func (in *lineRecorder) Complete(ctx context.Context) error {
+	return nil
+}
+
+func TestSource_srcFile_lines(t *testing.T) {
+	f, err := testx.File("testdata/complex.sql")
+	if err != nil {
+		panic(err)
+	}
+
+	src := &srcFile{f: f}
+	defer func() { _ = src.Close() }()
+
+	actual := &lineRecorder{}
+
+	err = src.Pipe(context.TODO(), actual)
+	if assert.NoError(t, err) {
+		expected := []int{
+			3, 5, 6, 7, 9, 11, 13, 15, 17, 19, 23, 27, 31, 32, 33, 35, 37, 38, 44, 47,
+		}
+		assert.Equal(t, expected, actual.lines)
+	}
+}
+
+func TestNewSource_remoteDigest(t *testing.T) {
+	content := "SELECT 1;"
+
+	testCases := []struct {
+		given  string
+		header map[string]string
+	}{
+		{
+			given:  "etag",
+			header: map[string]string{"ETag": `"v1"`},
+		},
+		{
+			given:  "last-modified",
+			header: map[string]string{"Last-Modified": "Mon, 19 Oct 2026 00:00:00 GMT"},
+		},
+		{
+			given: "no validators",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.given, func(t *testing.T) {
+			var body string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tc.header {
+					w.Header().Set(k, v)
+				}
+
+				_, _ = io.WriteString(w, body)
+			}))
+			defer srv.Close()
+
+			digest := func() string {
+				src, err := NewSource(context.TODO(), srv.URL, 1, sqlx.Wait{})
+				if !assert.NoError(t, err) {
+					return ""
+				}
+
+				defer func() { _ = src.Close() }()
+
+				dg, err := src.Digest()
+				assert.NoError(t, err)
+
+				actual := &lineRecorder{}
+				if assert.NoError(t, src.Pipe(context.TODO(), actual)) {
+					assert.Len(t, actual.lines, 1)
+				}
+
+				return dg
+			}
+
+			body = content
+			first := digest()
+
+			body = content + "\n"
+			second := digest()
+
+			if tc.header != nil {
+				assert.Equal(t, first, second, "the validators are trusted")
+			} else {
+				assert.NotEqual(t, first, second, "the content is digested")
+			}
+		})
+	}
+}
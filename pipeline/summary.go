@@ -0,0 +1,129 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+const (
+	StatementDDL     = "ddl"
+	StatementDML     = "dml"
+	StatementTCL     = "tcl"
+	StatementDCL     = "dcl"
+	StatementUnknown = "unknown"
+)
+
+// Summary summarizes the statements of Source without executing.
+type Summary struct {
+	// Statements counts the statements by type.
+	Statements map[string]int
+	// Tables records the tables created or written by the statements,
+	// in order of their first appearance.
+	Tables []string
+	// Created records the tables created by the statements,
+	// in order of their first appearance.
+	Created []string
+	// InsertRows counts the inserting rows by table.
+	InsertRows map[string]int
+	// Unclassified records the statements cannot be classified.
+	Unclassified []string
+
+	drv       string
+	tables    map[string]struct{}
+	created   map[string]struct{}
+	bufTables map[string]string
+}
+
+// NewSummary returns a Receiver to summarize the statements parsed with the given driver.
+func NewSummary(drv string) *Summary {
+	return &Summary{
+		Statements: map[string]int{
+			StatementDDL:     0,
+			StatementDML:     0,
+			StatementTCL:     0,
+			StatementDCL:     0,
+			StatementUnknown: 0,
+		},
+		InsertRows: map[string]int{},
+		drv:        drv,
+		tables:     map[string]struct{}{},
+		created:    map[string]struct{}{},
+		bufTables:  map[string]string{},
+	}
+}
+
+func (in *Summary) Exec(ctx context.Context, sql string) error {
+	if sqlx.IsEmpty(sql) {
+		return nil
+	}
+
+	sqlp := sqlx.Parse(in.drv, sql)
+
+	switch {
+	case sqlp.Unknown():
+		in.Statements[StatementUnknown] += 1
+		in.Unclassified = append(in.Unclassified, sql)
+
+		return nil
+	case sqlp.DDL():
+		in.Statements[StatementDDL] += 1
+	case sqlp.DCL():
+		in.Statements[StatementDCL] += 1
+	default:
+		if _, ok := sqlp.TCL(); ok {
+			in.Statements[StatementTCL] += 1
+			return nil
+		}
+
+		in.Statements[StatementDML] += 1
+
+		inst, ok := sqlp.AsDMLInsert()
+		if ok {
+			tbl, exist := in.bufTables[inst.Prefix]
+			if !exist {
+				tbl, _ = sqlp.Table()
+				in.bufTables[inst.Prefix] = tbl
+			}
+
+			if tbl != "" {
+				in.InsertRows[tbl] += len(inst.Values)
+				in.touch(tbl)
+			}
+
+			return nil
+		}
+	}
+
+	if tbl, ok := sqlp.Table(); ok {
+		in.touch(tbl)
+
+		if sqlp.DDL() && sqlx.IsCreateTable(sqlp.Origin()) {
+			in.create(tbl)
+		}
+	}
+
+	return nil
+}
+
+func (in *Summary) Complete(ctx context.Context) error {
+	return nil
+}
+
+func (in *Summary) touch(tbl string) {
+	if _, exist := in.tables[tbl]; exist {
+		return
+	}
+
+	in.tables[tbl] = struct{}{}
+	in.Tables = append(in.Tables, tbl)
+}
+
+func (in *Summary) create(tbl string) {
+	if _, exist := in.created[tbl]; exist {
+		return
+	}
+
+	in.created[tbl] = struct{}{}
+	in.Created = append(in.Created, tbl)
+}
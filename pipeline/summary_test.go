@@ -0,0 +1,38 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+	"github.com/seal-io/terraform-provider-byteset/utils/testx"
+)
+
+func TestSummary(t *testing.T) {
+	f, err := testx.File("testdata/complex.sql")
+	if err != nil {
+		panic(err)
+	}
+
+	src := &srcFile{f: f}
+	defer func() { _ = src.Close() }()
+
+	actual := NewSummary(sqlx.MySQLDialect)
+
+	err = src.Pipe(context.TODO(), actual)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]int{
+			StatementDDL:     2,
+			StatementDML:     3,
+			StatementTCL:     0,
+			StatementDCL:     0,
+			StatementUnknown: 0,
+		}, actual.Statements)
+		assert.Equal(t, []string{"test"}, actual.Tables)
+		assert.Equal(t, []string{"test"}, actual.Created)
+		assert.Equal(t, map[string]int{"test": 2}, actual.InsertRows)
+		assert.Empty(t, actual.Unclassified)
+	}
+}
@@ -0,0 +1,9 @@
+--
+-- Dumping data for table `city`
+--
+
+LOCK TABLES `city` WRITE;
+/*!40000 ALTER TABLE `city` DISABLE KEYS */;
+INSERT INTO `city` VALUES (1,'Kabul','AFG'),(2,'Qandahar','AFG'),(3,'Herat','AFG');
+/*!40000 ALTER TABLE `city` ENABLE KEYS */;
+UNLOCK TABLES;
@@ -0,0 +1,185 @@
+package pipeline
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/sqlx"
+)
+
+// Problem describes the statement failed in validation.
+type Problem struct {
+	// Line is the source line number of the statement, 0 if unknown.
+	Line int
+	// SQL is the statement.
+	SQL string
+	// Error is the reason.
+	Error error
+	// Warning is true if the statement is ignored at piping rather than failed.
+	Warning bool
+}
+
+// Validation validates the statements of Source against the destination database without changing any data,
+// the inserting, updating or deleting statements are prepared(or explained) if the dialect allows,
+// and the others are only parsed.
+type Validation struct {
+	// Problems records the problems in order of the statements.
+	Problems []Problem
+
+	drv    string
+	db     *stdsql.DB
+	conn   *stdsql.Conn
+	tables map[string]struct{}
+}
+
+// NewValidation returns a Receiver to validate the statements against the given destination database,
+// the given w specifies how to wait for the database to be ready.
+func NewValidation(ctx context.Context, addr string, w sqlx.Wait) (*Validation, error) {
+	ctx = sqlx.MaskSecrets(ctx, addr)
+
+	// Load database.
+	drv, db, err := sqlx.LoadDatabase(addr, 1)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load database from %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+	}
+
+	// Detect connectivity.
+	err = sqlx.IsDatabaseConnected(ctx, db, w)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot connect database on %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+	}
+
+	// Validate in one session,
+	// a failed statement aborts the whole transaction on Postgres,
+	// so avoid validating inside a transaction.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot open session on %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+	}
+
+	if drv == sqlx.SQLServerDialect {
+		// Compile without executing.
+		_, err = conn.ExecContext(ctx, "SET NOEXEC ON")
+		if err != nil {
+			_ = conn.Close()
+			_ = db.Close()
+
+			return nil, fmt.Errorf("cannot disable executing on %q: %w", RedactAddress(addr), sqlx.RedactError(err, addr))
+		}
+	}
+
+	return &Validation{
+		drv:    drv,
+		db:     db,
+		conn:   conn,
+		tables: map[string]struct{}{},
+	}, nil
+}
+
+func (in *Validation) Close() error {
+	_ = in.conn.Close()
+	return in.db.Close()
+}
+
+func (in *Validation) Exec(ctx context.Context, sql string) error {
+	if sqlx.IsEmpty(sql) {
+		return nil
+	}
+
+	sqlp := sqlx.Parse(in.drv, sql)
+
+	switch {
+	case sqlp.Unknown():
+		// The statements of hook are executed verbatim, which cannot be validated.
+		if !isVerbatim(ctx) {
+			in.report(ctx, sql, errors.New("cannot classify the statement, which is ignored at piping"), true)
+		}
+
+		return nil
+	case sqlp.DDL():
+		// Record the created or altered table,
+		// which cannot be validated before creating.
+		if tbl, ok := sqlp.Table(); ok {
+			in.tables[tbl] = struct{}{}
+		}
+
+		return nil
+	case sqlp.DCL():
+		return nil
+	}
+
+	if _, ok := sqlp.TCL(); ok {
+		return nil
+	}
+
+	if tbl, ok := sqlp.Table(); ok {
+		if _, exist := in.tables[tbl]; exist {
+			return nil
+		}
+	}
+
+	if err := in.validate(ctx, sql); err != nil {
+		in.report(ctx, sql, err, false)
+	}
+
+	return nil
+}
+
+func (in *Validation) Complete(ctx context.Context) error {
+	return nil
+}
+
+// validate prepares or explains the given DML without executing.
+func (in *Validation) validate(ctx context.Context, sql string) error {
+	sql = strings.TrimRight(sql, "; \t\r\n")
+
+	switch in.drv {
+	case sqlx.MySQLDialect, sqlx.PostgresDialect:
+		stmt, err := in.conn.PrepareContext(ctx, sql)
+		if err != nil {
+			var myErr *mysql.MySQLError
+			if errors.As(err, &myErr) && myErr.Number == 1295 {
+				// Not supported in the prepared statement protocol.
+				return nil
+			}
+
+			return err
+		}
+
+		return stmt.Close()
+	case sqlx.SQLServerDialect:
+		_, err := in.conn.ExecContext(ctx, sql)
+		return err
+	case sqlx.OracleDialect:
+		// The plan is written to the session-private plan table.
+		_, err := in.conn.ExecContext(ctx, "EXPLAIN PLAN FOR "+sql)
+		return err
+	}
+
+	return nil
+}
+
+func (in *Validation) report(ctx context.Context, sql string, err error, warning bool) {
+	p := Problem{
+		Line:    LineFrom(ctx),
+		SQL:     sql,
+		Error:   err,
+		Warning: warning,
+	}
+
+	tflog.Debug(ctx, "Invalid statement", map[string]any{
+		"line":  p.Line,
+		"sql":   sqlx.RedactSQL(ctx, sql),
+		"error": err.Error(),
+	})
+
+	in.Problems = append(in.Problems, p)
+}
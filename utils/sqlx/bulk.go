@@ -0,0 +1,246 @@
+package sqlx
+
+import (
+	"go/constant"
+
+	vp "vitess.io/vitess/go/vt/sqlparser"
+
+	cp "github.com/cockroachdb/cockroach/pkg/sql/parser"
+	cpt "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	cptt "github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+type LiteralKind = uint
+
+const (
+	NullLiteral LiteralKind = iota
+	StringLiteral
+	IntegerLiteral
+	NumericLiteral
+	BooleanLiteral
+)
+
+// Literal holds the textual value of a constant,
+// Value is "t" or "f" for BooleanLiteral.
+type Literal struct {
+	Kind  LiteralKind
+	Value string
+}
+
+type DMLBulkInsert struct {
+	DMLInsert
+
+	// Schema is the unquoted schema of the inserting table,
+	// blank if not specified.
+	Schema string
+	// Table is the unquoted name of the inserting table.
+	Table string
+	// Columns are the unquoted names of the inserting columns,
+	// empty if not specified.
+	Columns []string
+	// Target is the inserting table and columns quoted as the statement,
+	// e.g. "MyTable" ("Id", name), only parsed by the postgres dialect.
+	Target string
+	// Rows are the literal values of DMLInsert.Values,
+	// nil if any value is not a literal.
+	Rows [][]Literal
+}
+
+func (p parsed) AsDMLBulkInsert() (DMLBulkInsert, bool) {
+	if p.stmtType != StatementTypeDMLSingle {
+		return DMLBulkInsert{}, false
+	}
+
+	if p.drv == PostgresDialect {
+		return bulkInsertPostgres(p.raw)
+	}
+
+	return bulkInsert(p.raw)
+}
+
+func bulkInsertPostgres(raw string) (DMLBulkInsert, bool) {
+	stmt, err := cp.ParseOne(raw)
+	if err != nil {
+		return DMLBulkInsert{}, false
+	}
+
+	is, ok := insertOfPostgres(stmt.AST)
+	if !ok {
+		return DMLBulkInsert{}, false
+	}
+
+	bi := DMLBulkInsert{DMLInsert: is}
+
+	in, ok := stmt.AST.(*cpt.Insert)
+	if !ok || in.OnConflict != nil {
+		return bi, true
+	}
+
+	te := in.Table
+	if ate, ok := te.(*cpt.AliasedTableExpr); ok {
+		te = ate.Expr
+	}
+
+	tn, ok := te.(*cpt.TableName)
+	if !ok {
+		return bi, true
+	}
+
+	vc, ok := in.Rows.Select.(*cpt.ValuesClause)
+	if !ok {
+		return bi, true
+	}
+
+	rows := make([][]Literal, 0, len(vc.Rows))
+
+	for _, r := range vc.Rows {
+		row := make([]Literal, 0, len(r))
+
+		for _, e := range r {
+			l, ok := literalOfPostgres(e)
+			if !ok {
+				return bi, true
+			}
+
+			row = append(row, l)
+		}
+
+		rows = append(rows, row)
+	}
+
+	if tn.ExplicitSchema {
+		bi.Schema = string(tn.SchemaName)
+	}
+	bi.Table = string(tn.ObjectName)
+
+	for i := range in.Columns {
+		bi.Columns = append(bi.Columns, string(in.Columns[i]))
+	}
+
+	// Format the identifiers as the statement,
+	// the unquoted ones are folded to lower case at parsing.
+	bi.Target = cpt.AsStringWithFlags(tn, cpt.FmtSimple)
+	if len(in.Columns) != 0 {
+		bi.Target += " (" + cpt.AsStringWithFlags(&in.Columns, cpt.FmtSimple) + ")"
+	}
+	bi.Rows = rows
+
+	return bi, true
+}
+
+func literalOfPostgres(e cpt.Expr) (Literal, bool) {
+	if e == cpt.DNull {
+		return Literal{Kind: NullLiteral}, true
+	}
+
+	switch t := e.(type) {
+	case *cpt.DBool:
+		if *t {
+			return Literal{Kind: BooleanLiteral, Value: "t"}, true
+		}
+
+		return Literal{Kind: BooleanLiteral, Value: "f"}, true
+	case *cpt.StrVal:
+		if t.AvailableTypes()[0] == cptt.Bytes {
+			return Literal{}, false
+		}
+
+		return Literal{Kind: StringLiteral, Value: t.RawString()}, true
+	case *cpt.NumVal:
+		v := cpt.AsStringWithFlags(t, cpt.FmtSimple)
+		if t.Kind() == constant.Int {
+			return Literal{Kind: IntegerLiteral, Value: v}, true
+		}
+
+		if t.Kind() == constant.Float {
+			return Literal{Kind: NumericLiteral, Value: v}, true
+		}
+	}
+
+	return Literal{}, false
+}
+
+func bulkInsert(raw string) (DMLBulkInsert, bool) {
+	stmt, err := vp.Parse(raw)
+	if err != nil {
+		return DMLBulkInsert{}, false
+	}
+
+	is, ok := insertOf(stmt)
+	if !ok {
+		return DMLBulkInsert{}, false
+	}
+
+	bi := DMLBulkInsert{DMLInsert: is}
+
+	in := stmt.(*vp.Insert)
+	if in.Ignore || len(in.Partitions) != 0 {
+		return bi, true
+	}
+
+	vs, ok := in.Rows.(vp.Values)
+	if !ok {
+		return bi, true
+	}
+
+	rows := make([][]Literal, 0, len(vs))
+
+	for _, r := range vs {
+		row := make([]Literal, 0, len(r))
+
+		for _, e := range r {
+			l, ok := literalOf(e)
+			if !ok {
+				return bi, true
+			}
+
+			row = append(row, l)
+		}
+
+		rows = append(rows, row)
+	}
+
+	bi.Schema = in.Table.Qualifier.String()
+	bi.Table = in.Table.Name.String()
+
+	for i := range in.Columns {
+		bi.Columns = append(bi.Columns, in.Columns[i].String())
+	}
+	bi.Rows = rows
+
+	return bi, true
+}
+
+func literalOf(e vp.Expr) (Literal, bool) {
+	switch t := e.(type) {
+	case *vp.NullVal:
+		return Literal{Kind: NullLiteral}, true
+	case vp.BoolVal:
+		if t {
+			return Literal{Kind: BooleanLiteral, Value: "t"}, true
+		}
+
+		return Literal{Kind: BooleanLiteral, Value: "f"}, true
+	case *vp.Literal:
+		switch t.Type {
+		case vp.StrVal:
+			return Literal{Kind: StringLiteral, Value: t.Val}, true
+		case vp.IntVal:
+			return Literal{Kind: IntegerLiteral, Value: t.Val}, true
+		case vp.DecimalVal, vp.FloatVal:
+			return Literal{Kind: NumericLiteral, Value: t.Val}, true
+		}
+	case *vp.UnaryExpr:
+		if t.Operator != vp.UMinusOp {
+			break
+		}
+
+		l, ok := literalOf(t.Expr)
+		if ok && (l.Kind == IntegerLiteral || l.Kind == NumericLiteral) {
+			l.Value = "-" + l.Value
+			return l, true
+		}
+	}
+
+	return Literal{}, false
+}
@@ -0,0 +1,133 @@
+package sqlx
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsed_AsDMLBulkInsert(t *testing.T) {
+	type (
+		input struct {
+			drv string
+			sql string
+		}
+		output struct {
+			schema  string
+			table   string
+			columns []string
+			target  string
+			rows    [][]Literal
+		}
+	)
+
+	tc := []struct {
+		given    input
+		expected output
+	}{
+		{
+			given: input{
+				drv: MySQLDialect,
+				sql: "INSERT INTO `db`.`city` (`ID`, `Name`, `Rate`, `Active`) VALUES " +
+					"(79, 'Lan\\'ús', -1.5, TRUE), (80, NULL, 2e3, false);",
+			},
+			expected: output{
+				schema:  "db",
+				table:   "city",
+				columns: []string{"ID", "Name", "Rate", "Active"},
+				rows: [][]Literal{
+					{
+						{Kind: IntegerLiteral, Value: "79"},
+						{Kind: StringLiteral, Value: "Lan'ús"},
+						{Kind: NumericLiteral, Value: "-1.5"},
+						{Kind: BooleanLiteral, Value: "t"},
+					},
+					{
+						{Kind: IntegerLiteral, Value: "80"},
+						{Kind: NullLiteral},
+						{Kind: NumericLiteral, Value: "2e3"},
+						{Kind: BooleanLiteral, Value: "f"},
+					},
+				},
+			},
+		},
+		{
+			given: input{
+				drv: MySQLDialect,
+				sql: "INSERT INTO city VALUES (1, NOW());",
+			},
+			expected: output{},
+		},
+		{
+			given: input{
+				drv: PostgresDialect,
+				sql: "INSERT INTO public.customers (id, name, rate, active) VALUES " +
+					"(1, e'K\\u00F6niglich', -0.5, true), (2, NULL, 3, false);",
+			},
+			expected: output{
+				schema:  "public",
+				table:   "customers",
+				columns: []string{"id", "name", "rate", "active"},
+				target:  "public.customers (id, name, rate, active)",
+				rows: [][]Literal{
+					{
+						{Kind: IntegerLiteral, Value: "1"},
+						{Kind: StringLiteral, Value: "Königlich"},
+						{Kind: NumericLiteral, Value: "-0.5"},
+						{Kind: BooleanLiteral, Value: "t"},
+					},
+					{
+						{Kind: IntegerLiteral, Value: "2"},
+						{Kind: NullLiteral},
+						{Kind: IntegerLiteral, Value: "3"},
+						{Kind: BooleanLiteral, Value: "f"},
+					},
+				},
+			},
+		},
+		{
+			given: input{
+				drv: PostgresDialect,
+				sql: `INSERT INTO "Sales"."Customers" ("ID", Name) VALUES (1, 'a');`,
+			},
+			expected: output{
+				schema:  "Sales",
+				table:   "Customers",
+				columns: []string{"ID", "name"},
+				target:  `"Sales"."Customers" ("ID", name)`,
+				rows: [][]Literal{
+					{
+						{Kind: IntegerLiteral, Value: "1"},
+						{Kind: StringLiteral, Value: "a"},
+					},
+				},
+			},
+		},
+		{
+			given: input{
+				drv: PostgresDialect,
+				sql: "INSERT INTO customers VALUES (1, '{}'::jsonb);",
+			},
+			expected: output{},
+		},
+	}
+
+	for i := range tc {
+		c := tc[i]
+		t.Run("case "+strconv.Itoa(i), func(t *testing.T) {
+			ret, ok := Parse(c.given.drv, c.given.sql).AsDMLBulkInsert()
+			if assert.True(t, ok) {
+				actual := output{
+					schema:  ret.Schema,
+					table:   ret.Table,
+					columns: ret.Columns,
+					target:  ret.Target,
+					rows:    ret.Rows,
+				}
+				assert.Equal(t, c.expected, actual)
+				assert.NotEmpty(t, ret.Values)
+			}
+		})
+	}
+}
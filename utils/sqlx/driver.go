@@ -3,7 +3,12 @@ package sqlx
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -65,7 +70,302 @@ func ParseAddress(addr string) (drv, dsn string, err error) {
 	return
 }
 
-func LoadDatabase(addr string, addrConnMax int) (drv string, db *sql.DB, err error) {
+// WithCredentials replaces the credentials of the given database address,
+// keeps the original one if the given username is blank.
+func WithCredentials(addr, username, password string) (string, error) {
+	if username == "" {
+		return addr, nil
+	}
+
+	drv, dsn, err := ParseAddress(addr)
+	if err != nil {
+		return "", err
+	}
+
+	if drv == MySQLDialect {
+		// Format: [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...].
+		scheme := addr[:len(addr)-len(dsn)]
+
+		rest := dsn
+		if i := strings.LastIndex(dsn, "/"); i >= 0 {
+			if j := strings.LastIndex(dsn[:i], "@"); j >= 0 {
+				rest = dsn[j+1:]
+			}
+		}
+
+		cred := username
+		if password != "" {
+			cred += ":" + password
+		}
+
+		return scheme + cred + "@" + rest, nil
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse database address: %w", RedactError(err, addr))
+	}
+
+	if password != "" {
+		u.User = url.UserPassword(username, password)
+	} else {
+		u.User = url.User(username)
+	}
+
+	return u.String(), nil
+}
+
+// RedactAddress returns the given address with the secrets masked,
+// i.e. the password of userinfo and the password parameters.
+func RedactAddress(addr string) string {
+	spans := secretSpans(addr)
+
+	for i := len(spans) - 1; i >= 0; i-- {
+		addr = addr[:spans[i][0]] + "xxxxx" + addr[spans[i][1]:]
+	}
+
+	return addr
+}
+
+// RedactError returns the given error with the secrets of the given address masked,
+// returns the error as is if it does not contain any secret.
+func RedactError(err error, addr string) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+
+	for _, s := range Secrets(addr) {
+		msg = strings.ReplaceAll(msg, s, "xxxxx")
+	}
+
+	if msg == err.Error() {
+		return err
+	}
+
+	return redactedError{msg: msg, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e redactedError) Error() string {
+	return e.msg
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
+
+// Secrets returns the secrets of the given address,
+// including the escaped and unescaped forms.
+func Secrets(addr string) []string {
+	spans := secretSpans(addr)
+	ss := make([]string, 0, 2*len(spans))
+
+	for _, sp := range spans {
+		s := addr[sp[0]:sp[1]]
+		ss = append(ss, s)
+
+		if us, err := url.QueryUnescape(s); err == nil && us != s {
+			ss = append(ss, us)
+		}
+	}
+
+	return ss
+}
+
+// secretParams holds the lower case names of the parameters carrying secrets.
+var secretParams = map[string]struct{}{
+	"password": {},
+	"pwd":      {},
+	"sslkey":   {},
+}
+
+// secretSpans returns the [start, end) spans of the secrets in the given address in order,
+// which is parsed textually, so that the malformed address can be redacted too.
+func secretSpans(addr string) [][2]int {
+	i := strings.Index(addr, "://")
+	if i < 0 {
+		return nil
+	}
+	i += 3
+
+	var (
+		spans [][2]int
+		end   = -1
+		query = -1
+	)
+
+	if drv, _, err := ParseAddress(addr); err == nil && drv == MySQLDialect {
+		// Format: [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...],
+		// the driver splits the credentials at the last @ before the last /.
+		if j := strings.LastIndex(addr, "/"); j >= i {
+			end = strings.LastIndex(addr[:j], "@")
+
+			if k := strings.Index(addr[j:], "?"); k >= 0 {
+				query = j + k
+			}
+		}
+	} else {
+		query = strings.Index(addr, "?")
+
+		auth := addr
+		if query >= 0 {
+			auth = addr[:query]
+		}
+
+		end = strings.LastIndex(auth, "@")
+	}
+
+	if end >= i {
+		if k := strings.Index(addr[i:end], ":"); k >= 0 && i+k+1 < end {
+			spans = append(spans, [2]int{i + k + 1, end})
+		}
+	}
+
+	if query >= 0 {
+		off := query + 1
+
+		for _, kv := range strings.Split(addr[off:], "&") {
+			k, v, ok := strings.Cut(kv, "=")
+			if ok && v != "" {
+				uk, err := url.QueryUnescape(k)
+				if err != nil {
+					uk = k
+				}
+
+				if _, secret := secretParams[strings.ToLower(uk)]; secret {
+					spans = append(spans, [2]int{off + len(k) + 1, off + len(kv)})
+				}
+			}
+
+			off += len(kv) + 1
+		}
+	}
+
+	return spans
+}
+
+// Endpoint describes the database address in structure,
+// which avoids escaping the credentials by hand.
+type Endpoint struct {
+	// Driver specifies the database driver,
+	// mysql, maria, mariadb, postgres, postgresql, oracle, mssql or sqlserver.
+	Driver string
+	// Host specifies the host of the database.
+	Host string
+	// Port specifies the port of the database, uses the driver default if not positive.
+	Port int
+	// Username specifies the username to log in.
+	Username string
+	// Password specifies the password to log in.
+	Password string
+	// Database specifies the database to connect,
+	// which is the service name of oracle.
+	Database string
+	// Params specifies the driver parameters.
+	Params map[string]string
+}
+
+// BuildAddress returns the database address of the given Endpoint,
+// which can be parsed by ParseAddress.
+func BuildAddress(e Endpoint) (string, error) {
+	if e.Host == "" {
+		return "", errors.New("blank database host")
+	}
+
+	host := e.Host
+	if e.Port > 0 {
+		host += ":" + strconv.Itoa(e.Port)
+	}
+
+	q := url.Values{}
+	for k, v := range e.Params {
+		q.Set(k, v)
+	}
+
+	switch e.Driver {
+	case "mysql", "maria", "mariadb":
+		// Format: [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...],
+		// the driver splits the credentials at the last @ before the last /,
+		// so the password is not escaped.
+		var cred string
+		if e.Username != "" {
+			cred = e.Username
+			if e.Password != "" {
+				cred += ":" + e.Password
+			}
+			cred += "@"
+		}
+
+		addr := e.Driver + "://" + cred + "tcp(" + host + ")/" + e.Database
+		if len(q) != 0 {
+			addr += "?" + q.Encode()
+		}
+
+		return addr, nil
+	case "postgres", "postgresql", "oracle", "mssql", "sqlserver":
+	default:
+		return "", fmt.Errorf("unknown database driver %q", e.Driver)
+	}
+
+	u := url.URL{
+		Scheme: e.Driver,
+		Host:   host,
+	}
+
+	if e.Username != "" {
+		if e.Password != "" {
+			u.User = url.UserPassword(e.Username, e.Password)
+		} else {
+			u.User = url.User(e.Username)
+		}
+	}
+
+	if e.Database != "" {
+		switch e.Driver {
+		case "mssql", "sqlserver":
+			q.Set("database", e.Database)
+		default:
+			u.Path = "/" + e.Database
+		}
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Placeholder returns the i-th(starts from 1) bind variable of the given driver.
+func Placeholder(drv string, i int) string {
+	switch drv {
+	case PostgresDialect:
+		return "$" + strconv.Itoa(i)
+	case OracleDialect:
+		return ":" + strconv.Itoa(i)
+	case SQLServerDialect:
+		return "@p" + strconv.Itoa(i)
+	}
+
+	return "?"
+}
+
+// LoadDatabase opens the database of the given address,
+// the given inits are executed at opening each session,
+// the TLS files referred by the address are removed at closing the database.
+func LoadDatabase(addr string, addrConnMax int, inits ...string) (drv string, db *sql.DB, err error) {
+	pems := pemPathsOf(addr)
+
+	defer func() {
+		if err != nil {
+			releasePEM(pems...)
+		}
+	}()
+
 	drv, dsn, err := ParseAddress(addr)
 	if err != nil {
 		return
@@ -76,6 +376,26 @@ func LoadDatabase(addr string, addrConnMax int) (drv string, db *sql.DB, err err
 		return
 	}
 
+	if len(inits) != 0 || len(pems) != 0 {
+		var c driver.Connector
+
+		c, err = connectorOf(db.Driver(), dsn)
+		if err != nil {
+			return
+		}
+
+		if len(inits) != 0 {
+			c = initConnector{Connector: c, inits: inits}
+		}
+
+		if len(pems) != 0 {
+			c = pemConnector{Connector: c, paths: pems}
+		}
+
+		_ = db.Close()
+		db = sql.OpenDB(c)
+	}
+
 	if addrConnMax <= 0 {
 		addrConnMax = 25
 	}
@@ -88,17 +408,117 @@ func LoadDatabase(addr string, addrConnMax int) (drv string, db *sql.DB, err err
 	return
 }
 
-func IsDatabaseConnected(ctx context.Context, db *sql.DB) (perr error) {
-	err := wait.PollImmediateUntil(5*time.Second,
+func connectorOf(d driver.Driver, dsn string) (driver.Connector, error) {
+	if dc, ok := d.(driver.DriverContext); ok {
+		return dc.OpenConnector(dsn)
+	}
+
+	return dsnConnector{dsn: dsn, drv: d}, nil
+}
+
+type dsnConnector struct {
+	dsn string
+	drv driver.Driver
+}
+
+func (c dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.drv.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+// initConnector executes the inits after connecting.
+type initConnector struct {
+	driver.Connector
+
+	inits []string
+}
+
+func (c initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range c.inits {
+		err = execDriverConn(ctx, conn, s)
+		if err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("cannot initialize session with %q: %w", s, err)
+		}
+	}
+
+	return conn, nil
+}
+
+func execDriverConn(ctx context.Context, conn driver.Conn, s string) error {
+	if ex, ok := conn.(driver.ExecerContext); ok {
+		_, err := ex.ExecContext(ctx, s, nil)
+		if !errors.Is(err, driver.ErrSkip) {
+			return err
+		}
+	}
+
+	st, err := conn.Prepare(s)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = st.Close() }()
+
+	sc, ok := st.(driver.StmtExecContext)
+	if !ok {
+		return errors.New("cannot execute statement with context")
+	}
+
+	_, err = sc.ExecContext(ctx, nil)
+
+	return err
+}
+
+// Wait specifies how to wait for the database to be ready.
+type Wait struct {
+	// Timeout specifies the maximum waiting duration,
+	// defaults to 1 minute if not positive.
+	Timeout time.Duration
+	// Interval specifies the waiting duration between detections,
+	// defaults to 5 seconds if not positive.
+	Interval time.Duration
+	// Query specifies the query to detect the readiness,
+	// the database is ready if the query returns any row,
+	// pings the database if blank.
+	Query string
+}
+
+// IsDatabaseConnected waits until the given database is ready,
+// returns the last detecting error if timeout.
+func IsDatabaseConnected(ctx context.Context, db *sql.DB, w Wait) (perr error) {
+	if w.Timeout <= 0 {
+		w.Timeout = time.Minute
+	}
+
+	if w.Interval <= 0 {
+		w.Interval = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.Timeout)
+	defer cancel()
+
+	err := wait.ExponentialBackoffWithContext(ctx,
+		wait.Backoff{
+			Duration: w.Interval,
+			Steps:    math.MaxInt32,
+		},
 		func() (bool, error) {
-			perr = db.PingContext(ctx)
+			perr = isDatabaseReady(ctx, db, w.Query)
 			if perr != nil {
-				tflog.Error(ctx, "Cannot ping database", map[string]any{"error": perr})
+				tflog.Error(ctx, "Cannot detect database", map[string]any{"error": perr})
 			}
 
-			return perr == nil, ctx.Err()
+			return perr == nil, nil
 		},
-		ctx.Done(),
 	)
 	if err != nil {
 		if perr == nil {
@@ -108,3 +528,26 @@ func IsDatabaseConnected(ctx context.Context, db *sql.DB) (perr error) {
 
 	return
 }
+
+func isDatabaseReady(ctx context.Context, db *sql.DB, query string) error {
+	if query == "" {
+		return db.PingContext(ctx)
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		return errors.New("readiness query returns no rows")
+	}
+
+	return nil
+}
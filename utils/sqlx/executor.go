@@ -39,7 +39,33 @@ func Exec(ctx context.Context, ex Executor, sql string, args ...any) error {
 		}
 	}
 
-	tflog.Debug(ctx, "Executed", map[string]any{"sql": sql, "args": args})
+	tflog.Debug(ctx, "Executed", logFields(ctx, sql, args))
 
 	return nil
 }
+
+// ExecAffected is similar to Exec,
+// but returns the number of the rows affected by the given SQL, -1 if unknown.
+func ExecAffected(ctx context.Context, ex Executor, sql string, args ...any) (int64, error) {
+	r, err := ex.ExecContext(ctx, sql, args...)
+	if err != nil {
+		if !isIgnorableError(err) {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	tflog.Debug(ctx, "Executed", logFields(ctx, sql, args))
+
+	if r == nil {
+		return -1, nil
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return -1, nil
+	}
+
+	return n, nil
+}
@@ -0,0 +1,297 @@
+package sqlx
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"fmt"
+)
+
+type (
+	DatabaseInfo struct {
+		Version    string
+		Database   string
+		Schema     string
+		Charset    string
+		Collation  string
+		Tables     []TableInfo
+		Extensions map[string]string
+	}
+
+	TableInfo struct {
+		Name string
+		// Rows is the approximate row count.
+		Rows int64
+	}
+)
+
+type (
+	ForeignKey struct {
+		Name       string
+		Table      string
+		Columns    []string
+		RefTable   string
+		RefColumns []string
+	}
+
+	UniqueKey struct {
+		Name    string
+		Table   string
+		Columns []string
+	}
+)
+
+type introspectQueries struct {
+	version           string
+	current           string
+	charset           string
+	tables            string
+	extensions        string
+	foreignKeys       string
+	foreignKeyColumns string
+	uniqueKeyColumns  string
+}
+
+var introspectQueriesOf = map[string]introspectQueries{
+	MySQLDialect: {
+		version: `SELECT VERSION()`,
+		current: `SELECT DATABASE(), DATABASE()`,
+		charset: `SELECT @@character_set_database, @@collation_database`,
+		tables: `SELECT table_name, COALESCE(table_rows, 0) FROM information_schema.tables
+WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name`,
+		extensions: `SELECT plugin_name, plugin_version FROM information_schema.plugins
+WHERE plugin_status = 'ACTIVE'`,
+		foreignKeys: `SELECT table_name, referenced_table_name FROM information_schema.referential_constraints
+WHERE constraint_schema = DATABASE()`,
+		foreignKeyColumns: `SELECT constraint_name, table_name, column_name, referenced_table_name, referenced_column_name
+FROM information_schema.key_column_usage
+WHERE table_schema = DATABASE() AND referenced_table_name IS NOT NULL
+ORDER BY table_name, constraint_name, ordinal_position`,
+		uniqueKeyColumns: `SELECT index_name, table_name, column_name FROM information_schema.statistics
+WHERE table_schema = DATABASE() AND non_unique = 0 AND index_name <> 'PRIMARY'
+ORDER BY table_name, index_name, seq_in_index`,
+	},
+	PostgresDialect: {
+		version: `SHOW server_version`,
+		current: `SELECT current_database(), current_schema()`,
+		charset: `SELECT pg_encoding_to_char(encoding), datcollate FROM pg_database
+WHERE datname = current_database()`,
+		tables: `SELECT c.relname, GREATEST(c.reltuples, 0)::bigint FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p') AND n.nspname = current_schema() ORDER BY c.relname`,
+		extensions: `SELECT extname, extversion FROM pg_extension`,
+		foreignKeys: `SELECT cl.relname, pcl.relname FROM pg_constraint c
+JOIN pg_class cl ON cl.oid = c.conrelid
+JOIN pg_class pcl ON pcl.oid = c.confrelid
+WHERE c.contype = 'f'`,
+		foreignKeyColumns: `SELECT c.conname, cl.relname, a.attname, pcl.relname, pa.attname FROM pg_constraint c
+JOIN pg_class cl ON cl.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = cl.relnamespace
+JOIN pg_class pcl ON pcl.oid = c.confrelid
+CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+JOIN pg_attribute pa ON pa.attrelid = c.confrelid AND pa.attnum = k.refattnum
+WHERE c.contype = 'f' AND n.nspname = current_schema()
+ORDER BY cl.relname, c.conname, k.ord`,
+	},
+	SQLServerDialect: {
+		version: `SELECT CAST(SERVERPROPERTY('ProductVersion') AS NVARCHAR(128))`,
+		current: `SELECT DB_NAME(), SCHEMA_NAME()`,
+		charset: `SELECT CAST(COLLATIONPROPERTY(CAST(DATABASEPROPERTYEX(DB_NAME(), 'Collation') AS NVARCHAR(128)),
+'CodePage') AS NVARCHAR(16)), CAST(DATABASEPROPERTYEX(DB_NAME(), 'Collation') AS NVARCHAR(128))`,
+		tables: `SELECT t.name, SUM(p.rows) FROM sys.tables t
+JOIN sys.partitions p ON p.object_id = t.object_id AND p.index_id IN (0, 1)
+WHERE t.schema_id = SCHEMA_ID() GROUP BY t.name ORDER BY t.name`,
+		foreignKeys: `SELECT OBJECT_NAME(parent_object_id), OBJECT_NAME(referenced_object_id) FROM sys.foreign_keys`,
+	},
+	OracleDialect: {
+		version: `SELECT version FROM product_component_version WHERE ROWNUM = 1`,
+		current: `SELECT SYS_CONTEXT('USERENV', 'DB_NAME'), SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM dual`,
+		charset: `SELECT MAX(DECODE(parameter, 'NLS_CHARACTERSET', value)), MAX(DECODE(parameter, 'NLS_SORT', value))
+FROM nls_database_parameters`,
+		tables: `SELECT table_name, NVL(num_rows, 0) FROM user_tables ORDER BY table_name`,
+		foreignKeys: `SELECT a.table_name, b.table_name FROM user_constraints a
+JOIN user_constraints b ON b.owner = a.r_owner AND b.constraint_name = a.r_constraint_name
+WHERE a.constraint_type = 'R'`,
+	},
+}
+
+// Introspect returns the information of the database.
+func Introspect(ctx context.Context, drv string, db *stdsql.DB) (DatabaseInfo, error) {
+	qs, ok := introspectQueriesOf[drv]
+	if !ok {
+		return DatabaseInfo{}, errors.New("cannot introspect unknown driver database")
+	}
+
+	var (
+		info DatabaseInfo
+		err  error
+	)
+
+	err = db.QueryRowContext(ctx, qs.version).
+		Scan(&info.Version)
+	if err != nil {
+		return DatabaseInfo{}, fmt.Errorf("cannot query version: %w", err)
+	}
+
+	var dn, sn stdsql.NullString
+
+	err = db.QueryRowContext(ctx, qs.current).
+		Scan(&dn, &sn)
+	if err != nil {
+		return DatabaseInfo{}, fmt.Errorf("cannot query current database: %w", err)
+	}
+
+	info.Database, info.Schema = dn.String, sn.String
+
+	var cs, cl stdsql.NullString
+
+	err = db.QueryRowContext(ctx, qs.charset).
+		Scan(&cs, &cl)
+	if err != nil {
+		return DatabaseInfo{}, fmt.Errorf("cannot query charset: %w", err)
+	}
+
+	info.Charset, info.Collation = cs.String, cl.String
+
+	err = scanPairs(ctx, db, qs.tables, func(n string, r stdsql.NullInt64) {
+		info.Tables = append(info.Tables, TableInfo{Name: n, Rows: r.Int64})
+	})
+	if err != nil {
+		return DatabaseInfo{}, fmt.Errorf("cannot query tables: %w", err)
+	}
+
+	info.Extensions = map[string]string{}
+
+	if qs.extensions != "" {
+		err = scanPairs(ctx, db, qs.extensions, func(n string, v stdsql.NullString) {
+			info.Extensions[n] = v.String
+		})
+		if err != nil {
+			return DatabaseInfo{}, fmt.Errorf("cannot query extensions: %w", err)
+		}
+	}
+
+	return info, nil
+}
+
+// scanPairs scans the two columns result of the given query.
+func scanPairs[T any](ctx context.Context, db *stdsql.DB, query string, fn func(string, T)) error {
+	rs, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rs.Close() }()
+
+	for rs.Next() {
+		var (
+			k string
+			v T
+		)
+
+		if err = rs.Scan(&k, &v); err != nil {
+			return err
+		}
+
+		fn(k, v)
+	}
+
+	return rs.Err()
+}
+
+// ForeignKeys returns the referenced tables indexed by the referencing table.
+func ForeignKeys(ctx context.Context, drv string, db *stdsql.DB) (map[string][]string, error) {
+	qs, ok := introspectQueriesOf[drv]
+	if !ok {
+		return nil, errors.New("cannot introspect unknown driver database")
+	}
+
+	fks := map[string][]string{}
+
+	err := scanPairs(ctx, db, qs.foreignKeys, func(n string, r string) {
+		fks[n] = append(fks[n], r)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot query foreign keys: %w", err)
+	}
+
+	return fks, nil
+}
+
+// ForeignKeyColumns returns the foreign keys of the current schema with columns.
+func ForeignKeyColumns(ctx context.Context, drv string, q Queryer) ([]ForeignKey, error) {
+	qs, ok := introspectQueriesOf[drv]
+	if !ok || qs.foreignKeyColumns == "" {
+		return nil, fmt.Errorf("cannot introspect foreign key columns on %s", drv)
+	}
+
+	rs, err := q.QueryContext(ctx, qs.foreignKeyColumns)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query foreign key columns: %w", err)
+	}
+
+	defer func() { _ = rs.Close() }()
+
+	var fks []ForeignKey
+
+	for rs.Next() {
+		var n, t, c, rt, rc string
+
+		if err = rs.Scan(&n, &t, &c, &rt, &rc); err != nil {
+			return nil, fmt.Errorf("cannot scan foreign key columns: %w", err)
+		}
+
+		if l := len(fks) - 1; l >= 0 && fks[l].Name == n && fks[l].Table == t {
+			fks[l].Columns = append(fks[l].Columns, c)
+			fks[l].RefColumns = append(fks[l].RefColumns, rc)
+
+			continue
+		}
+
+		fks = append(fks, ForeignKey{
+			Name:       n,
+			Table:      t,
+			Columns:    []string{c},
+			RefTable:   rt,
+			RefColumns: []string{rc},
+		})
+	}
+
+	return fks, rs.Err()
+}
+
+// UniqueKeyColumns returns the unique keys of the current schema with columns,
+// excludes the primary keys.
+func UniqueKeyColumns(ctx context.Context, drv string, q Queryer) ([]UniqueKey, error) {
+	qs, ok := introspectQueriesOf[drv]
+	if !ok || qs.uniqueKeyColumns == "" {
+		return nil, fmt.Errorf("cannot introspect unique key columns on %s", drv)
+	}
+
+	rs, err := q.QueryContext(ctx, qs.uniqueKeyColumns)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query unique key columns: %w", err)
+	}
+
+	defer func() { _ = rs.Close() }()
+
+	var uks []UniqueKey
+
+	for rs.Next() {
+		var n, t, c string
+
+		if err = rs.Scan(&n, &t, &c); err != nil {
+			return nil, fmt.Errorf("cannot scan unique key columns: %w", err)
+		}
+
+		if l := len(uks) - 1; l >= 0 && uks[l].Name == n && uks[l].Table == t {
+			uks[l].Columns = append(uks[l].Columns, c)
+			continue
+		}
+
+		uks = append(uks, UniqueKey{Name: n, Table: t, Columns: []string{c}})
+	}
+
+	return uks, rs.Err()
+}
@@ -0,0 +1,312 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// introspectConnector connects to the fake database,
+// which returns the rows of the query in rows, or fails the query in fails.
+type introspectConnector struct {
+	rows  map[string][][]driver.Value
+	fails map[string]error
+}
+
+func (c introspectConnector) Connect(context.Context) (driver.Conn, error) {
+	return introspectConn{c: c}, nil
+}
+
+func (c introspectConnector) Driver() driver.Driver {
+	return nil
+}
+
+type introspectConn struct {
+	c introspectConnector
+}
+
+func (c introspectConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c introspectConn) Close() error {
+	return nil
+}
+
+func (c introspectConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c introspectConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if err := c.c.fails[query]; err != nil {
+		return nil, err
+	}
+
+	return &introspectRows{vals: c.c.rows[query]}, nil
+}
+
+type introspectRows struct {
+	vals [][]driver.Value
+}
+
+func (r *introspectRows) Columns() []string {
+	if len(r.vals) == 0 {
+		return []string{"_"}
+	}
+
+	return make([]string, len(r.vals[0]))
+}
+
+func (r *introspectRows) Close() error {
+	return nil
+}
+
+func (r *introspectRows) Next(dest []driver.Value) error {
+	if len(r.vals) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.vals[0])
+	r.vals = r.vals[1:]
+
+	return nil
+}
+
+func TestIntrospect(t *testing.T) {
+	type input struct {
+		drv  string
+		conn introspectConnector
+	}
+
+	var (
+		mysqlQueries  = introspectQueriesOf[MySQLDialect]
+		mssqlQueries  = introspectQueriesOf[SQLServerDialect]
+		pgQueries     = introspectQueriesOf[PostgresDialect]
+		mysqlDatabase = introspectConnector{
+			rows: map[string][][]driver.Value{
+				mysqlQueries.version: {{"8.0.36"}},
+				mysqlQueries.current: {{"byteset", "byteset"}},
+				mysqlQueries.charset: {{"utf8mb4", "utf8mb4_0900_ai_ci"}},
+				mysqlQueries.tables: {
+					{"city", int64(4079)},
+					{"country", int64(239)},
+				},
+				mysqlQueries.extensions: {
+					{"InnoDB", "8.0"},
+					{"mysql_native_password", nil},
+				},
+			},
+		}
+	)
+
+	tc := []struct {
+		name     string
+		given    input
+		expected DatabaseInfo
+		wantErr  bool
+	}{
+		{
+			name: "mysql",
+			given: input{
+				drv:  MySQLDialect,
+				conn: mysqlDatabase,
+			},
+			expected: DatabaseInfo{
+				Version:   "8.0.36",
+				Database:  "byteset",
+				Schema:    "byteset",
+				Charset:   "utf8mb4",
+				Collation: "utf8mb4_0900_ai_ci",
+				Tables: []TableInfo{
+					{Name: "city", Rows: 4079},
+					{Name: "country", Rows: 239},
+				},
+				Extensions: map[string]string{
+					"InnoDB":                "8.0",
+					"mysql_native_password": "",
+				},
+			},
+		},
+		{
+			name: "mssql without extensions",
+			given: input{
+				drv: SQLServerDialect,
+				conn: introspectConnector{
+					rows: map[string][][]driver.Value{
+						mssqlQueries.version: {{"16.0.1000.6"}},
+						mssqlQueries.current: {{"byteset", "dbo"}},
+						mssqlQueries.charset: {{"1252", "SQL_Latin1_General_CP1_CI_AS"}},
+						mssqlQueries.tables:  {{"city", nil}},
+					},
+				},
+			},
+			expected: DatabaseInfo{
+				Version:    "16.0.1000.6",
+				Database:   "byteset",
+				Schema:     "dbo",
+				Charset:    "1252",
+				Collation:  "SQL_Latin1_General_CP1_CI_AS",
+				Tables:     []TableInfo{{Name: "city", Rows: 0}},
+				Extensions: map[string]string{},
+			},
+		},
+		{
+			name: "postgres without current schema",
+			given: input{
+				drv: PostgresDialect,
+				conn: introspectConnector{
+					rows: map[string][][]driver.Value{
+						pgQueries.version: {{"15.4"}},
+						pgQueries.current: {{"byteset", nil}},
+						pgQueries.charset: {{"UTF8", "en_US.utf8"}},
+					},
+				},
+			},
+			expected: DatabaseInfo{
+				Version:    "15.4",
+				Database:   "byteset",
+				Charset:    "UTF8",
+				Collation:  "en_US.utf8",
+				Extensions: map[string]string{},
+			},
+		},
+		{
+			name: "unknown driver",
+			given: input{
+				drv:  "sqlite",
+				conn: mysqlDatabase,
+			},
+			wantErr: true,
+		},
+		{
+			name: "version not found",
+			given: input{
+				drv:  MySQLDialect,
+				conn: introspectConnector{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tables query failed",
+			given: input{
+				drv: MySQLDialect,
+				conn: introspectConnector{
+					rows: mysqlDatabase.rows,
+					fails: map[string]error{
+						mysqlQueries.tables: errors.New("access denied"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			db := sql.OpenDB(c.given.conn)
+			defer func() { _ = db.Close() }()
+
+			actual, err := Introspect(context.TODO(), c.given.drv, db)
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestForeignKeys(t *testing.T) {
+	q := introspectQueriesOf[MySQLDialect].foreignKeys
+
+	db := sql.OpenDB(introspectConnector{
+		rows: map[string][][]driver.Value{
+			q: {
+				{"city", "country"},
+				{"countrylanguage", "country"},
+				{"city", "province"},
+			},
+		},
+	})
+	defer func() { _ = db.Close() }()
+
+	actual, err := ForeignKeys(context.TODO(), MySQLDialect, db)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string][]string{
+			"city":            {"country", "province"},
+			"countrylanguage": {"country"},
+		}, actual)
+	}
+}
+
+func TestForeignKeyColumns(t *testing.T) {
+	q := introspectQueriesOf[PostgresDialect].foreignKeyColumns
+
+	db := sql.OpenDB(introspectConnector{
+		rows: map[string][][]driver.Value{
+			q: {
+				{"fk_city_country", "city", "country_code", "country", "code"},
+				{"fk_city_country", "city", "country_region", "country", "region"},
+				{"fk_lang_country", "countrylanguage", "country_code", "country", "code"},
+			},
+		},
+	})
+	defer func() { _ = db.Close() }()
+
+	actual, err := ForeignKeyColumns(context.TODO(), PostgresDialect, db)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []ForeignKey{
+			{
+				Name:       "fk_city_country",
+				Table:      "city",
+				Columns:    []string{"country_code", "country_region"},
+				RefTable:   "country",
+				RefColumns: []string{"code", "region"},
+			},
+			{
+				Name:       "fk_lang_country",
+				Table:      "countrylanguage",
+				Columns:    []string{"country_code"},
+				RefTable:   "country",
+				RefColumns: []string{"code"},
+			},
+		}, actual)
+	}
+
+	_, err = ForeignKeyColumns(context.TODO(), OracleDialect, db)
+	assert.Error(t, err, "not supported on oracle")
+}
+
+func TestUniqueKeyColumns(t *testing.T) {
+	q := introspectQueriesOf[MySQLDialect].uniqueKeyColumns
+
+	db := sql.OpenDB(introspectConnector{
+		rows: map[string][][]driver.Value{
+			q: {
+				{"uk_name", "city", "name"},
+				{"uk_name", "city", "district"},
+				{"uk_name", "country", "name"},
+			},
+		},
+	})
+	defer func() { _ = db.Close() }()
+
+	actual, err := UniqueKeyColumns(context.TODO(), MySQLDialect, db)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []UniqueKey{
+			{Name: "uk_name", Table: "city", Columns: []string{"name", "district"}},
+			{Name: "uk_name", Table: "country", Columns: []string{"name"}},
+		}, actual)
+	}
+
+	_, err = UniqueKeyColumns(context.TODO(), PostgresDialect, db)
+	assert.Error(t, err, "not supported on postgres")
+}
@@ -41,6 +41,11 @@ type Parsed interface {
 	DML() (DMLLevel, bool)
 	// AsDMLInsert returns the structuring insert statement if possible.
 	AsDMLInsert() (DMLInsert, bool)
+	// AsDMLBulkInsert is similar to AsDMLInsert,
+	// but also returns the literal rows for bulk loading if possible.
+	AsDMLBulkInsert() (DMLBulkInsert, bool)
+	// Table returns the name of the table which the Origin creates or writes to if possible.
+	Table() (string, bool)
 }
 
 func Parse(drv, sql string) Parsed {
@@ -101,13 +106,29 @@ func (p parsed) AsDMLInsert() (DMLInsert, bool) {
 	return parse(p.raw)
 }
 
+func (p parsed) Table() (string, bool) {
+	if p.stmtType != StatementTypeDDL && p.stmtType != StatementTypeDMLSingle {
+		return "", false
+	}
+
+	if p.drv == PostgresDialect {
+		return tablePostgres(p.raw)
+	}
+
+	return table(p.raw)
+}
+
 func parsePostgres(raw string) (DMLInsert, bool) {
 	stmt, err := cp.ParseOne(raw)
 	if err != nil {
 		return DMLInsert{}, false
 	}
 
-	switch in := stmt.AST.(type) {
+	return insertOfPostgres(stmt.AST)
+}
+
+func insertOfPostgres(stmt cpt.Statement) (DMLInsert, bool) {
+	switch in := stmt.(type) {
 	case *cpt.Insert:
 		if in.OnConflict.IsUpsertAlias() ||
 			cpt.HasReturningClause(in.Returning) ||
@@ -163,6 +184,10 @@ func parse(raw string) (DMLInsert, bool) {
 		return DMLInsert{}, false
 	}
 
+	return insertOf(stmt)
+}
+
+func insertOf(stmt vp.Statement) (DMLInsert, bool) {
 	in, ok := stmt.(*vp.Insert)
 	if !ok ||
 		in.Action != vp.InsertAct ||
@@ -207,3 +232,90 @@ func parse(raw string) (DMLInsert, bool) {
 
 	return is, true
 }
+
+func tablePostgres(raw string) (string, bool) {
+	stmt, err := cp.ParseOne(raw)
+	if err != nil {
+		return "", false
+	}
+
+	var te cpt.TableExpr
+
+	switch in := stmt.AST.(type) {
+	case *cpt.Insert:
+		te = in.Table
+	case *cpt.Update:
+		te = in.Table
+	case *cpt.Delete:
+		te = in.Table
+	case *cpt.CopyFrom:
+		te = &in.Table
+	case *cpt.CreateTable:
+		te = &in.Table
+	case *cpt.AlterTable:
+		tn := in.Table.ToTableName()
+		te = &tn
+	case *cpt.Truncate:
+		if len(in.Tables) != 1 {
+			return "", false
+		}
+		te = &in.Tables[0]
+	}
+
+	for {
+		switch t := te.(type) {
+		case *cpt.AliasedTableExpr:
+			te = t.Expr
+			continue
+		case *cpt.TableName:
+			return cpt.AsStringWithFlags(t, cpt.FmtSimple), true
+		}
+
+		return "", false
+	}
+}
+
+func table(raw string) (string, bool) {
+	stmt, err := vp.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+
+	var tn vp.TableName
+
+	switch in := stmt.(type) {
+	case *vp.Insert:
+		tn = in.Table
+	case *vp.Update:
+		tn = tableOf(in.TableExprs)
+	case *vp.Delete:
+		tn = tableOf(in.TableExprs)
+	case *vp.CreateTable:
+		tn = in.Table
+	case *vp.AlterTable:
+		tn = in.Table
+	case *vp.TruncateTable:
+		tn = in.Table
+	}
+
+	if tn.IsEmpty() {
+		return "", false
+	}
+
+	return vp.String(tn), true
+}
+
+func tableOf(tes vp.TableExprs) vp.TableName {
+	if len(tes) != 1 {
+		return vp.TableName{}
+	}
+
+	ate, ok := tes[0].(*vp.AliasedTableExpr)
+	if !ok {
+		return vp.TableName{}
+	}
+
+	tn, _ := ate.Expr.(vp.TableName)
+
+	return tn
+}
@@ -89,3 +89,80 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParsed_Table(t *testing.T) {
+	type (
+		input struct {
+			drv string
+			sql string
+		}
+		output struct {
+			ret string
+			ok  bool
+		}
+	)
+
+	tc := []struct {
+		given    input
+		expected output
+	}{
+		{
+			given: input{
+				drv: MySQLDialect,
+				sql: "INSERT INTO `city` (`ID`, `Name`) VALUES (79,'Lanús');",
+			},
+			expected: output{ret: "city", ok: true},
+		},
+		{
+			given: input{
+				drv: MySQLDialect,
+				sql: "CREATE TABLE company (id INTEGER PRIMARY KEY AUTO_INCREMENT, name TEXT NOT NULL);",
+			},
+			expected: output{ret: "company", ok: true},
+		},
+		{
+			given: input{
+				drv: MySQLDialect,
+				sql: "UPDATE byteset.company SET name = 'Paul' WHERE id = 1;",
+			},
+			expected: output{ret: "byteset.company", ok: true},
+		},
+		{
+			given: input{
+				drv: MySQLDialect,
+				sql: "DROP TABLE IF EXISTS company;",
+			},
+			expected: output{ok: false},
+		},
+		{
+			given: input{
+				drv: PostgresDialect,
+				sql: `INSERT INTO public.customers (customer_id, company_name) VALUES ('ISLAT', 'Island Trading');`,
+			},
+			expected: output{ret: "public.customers", ok: true},
+		},
+		{
+			given: input{
+				drv: PostgresDialect,
+				sql: `ALTER TABLE ONLY public.customers ADD CONSTRAINT pk_customers PRIMARY KEY (customer_id);`,
+			},
+			expected: output{ret: "public.customers", ok: true},
+		},
+		{
+			given: input{
+				drv: PostgresDialect,
+				sql: `SELECT pg_catalog.set_config('search_path', '', false);`,
+			},
+			expected: output{ok: false},
+		},
+	}
+
+	for i := range tc {
+		c := tc[i]
+		t.Run("case "+strconv.Itoa(i), func(t *testing.T) {
+			var actual output
+			actual.ret, actual.ok = Parse(c.given.drv, c.given.sql).Table()
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
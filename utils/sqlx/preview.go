@@ -28,6 +28,53 @@ const (
 	StatementTypeDMLMultiple
 )
 
+// IsEmpty returns true if the given SQL contains nothing but comments or semicolons.
+func IsEmpty(sql string) bool {
+	return strings.Trim(vp.StripLeadingComments(sql), "; \t\r\n") == ""
+}
+
+// IsBegin returns true if the given SQL starts a transaction,
+// like BEGIN or START TRANSACTION, but not LOCK TABLES.
+func IsBegin(sql string) bool {
+	ws := words(sql)
+	return len(ws) != 0 && (ws[0] == "begin" || len(ws) > 1 && ws[0] == "start" && ws[1] == "transaction")
+}
+
+// IsEnd returns true if the given SQL ends a transaction,
+// like COMMIT or ROLLBACK, but not ROLLBACK TO SAVEPOINT.
+func IsEnd(sql string) bool {
+	ws := words(sql)
+	return len(ws) != 0 && (ws[0] == "commit" || ws[0] == "rollback" && (len(ws) < 2 || ws[1] != "to"))
+}
+
+// IsInsert returns true if the given SQL is an INSERT or REPLACE statement.
+func IsInsert(sql string) bool {
+	ws := words(sql)
+	return len(ws) != 0 && (ws[0] == "insert" || ws[0] == "replace")
+}
+
+// IsCreateTable returns true if the given SQL is a CREATE TABLE statement without IF NOT EXISTS,
+// which must create the table if succeeded.
+func IsCreateTable(sql string) bool {
+	ws := words(sql)
+	if len(ws) == 0 || ws[0] != "create" {
+		return false
+	}
+
+	for i := 1; i < len(ws); i++ {
+		switch ws[i] {
+		case "global", "local", "temporary", "temp", "unlogged":
+			continue
+		case "table":
+			return !(i+3 < len(ws) && ws[i+1] == "if" && ws[i+2] == "not" && ws[i+3] == "exists")
+		}
+
+		break
+	}
+
+	return false
+}
+
 // Preview analyzes the beginning of the query using a simpler and faster
 // textual comparison to identify the statement type,
 // borrows from the vitess.io/vitess/go/vt/sqlparser.
@@ -0,0 +1,94 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBegin(t *testing.T) {
+	tc := []struct {
+		given    string
+		expected bool
+	}{
+		{given: "BEGIN", expected: true},
+		{given: "begin transaction;", expected: true},
+		{given: "/* comment */ START TRANSACTION", expected: true},
+		{given: "LOCK TABLES `city` WRITE", expected: false},
+		{given: "SAVEPOINT sp", expected: false},
+		{given: "", expected: false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.given, func(t *testing.T) {
+			assert.Equal(t, c.expected, IsBegin(c.given))
+		})
+	}
+}
+
+func TestIsEnd(t *testing.T) {
+	tc := []struct {
+		given    string
+		expected bool
+	}{
+		{given: "COMMIT", expected: true},
+		{given: "rollback;", expected: true},
+		{given: "ROLLBACK TO SAVEPOINT sp", expected: false},
+		{given: "UNLOCK TABLES", expected: false},
+		{given: "RELEASE SAVEPOINT sp", expected: false},
+		{given: "", expected: false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.given, func(t *testing.T) {
+			assert.Equal(t, c.expected, IsEnd(c.given))
+		})
+	}
+}
+
+func TestIsInsert(t *testing.T) {
+	tc := []struct {
+		given    string
+		expected bool
+	}{
+		{given: "INSERT INTO company SELECT * FROM backup", expected: true},
+		{given: "/* comment */ replace into company (name) values ('Paul')", expected: true},
+		{given: "UPDATE company SET name = 'insert'", expected: false},
+		{given: "", expected: false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.given, func(t *testing.T) {
+			assert.Equal(t, c.expected, IsInsert(c.given))
+		})
+	}
+}
+
+func TestIsCreateTable(t *testing.T) {
+	tc := []struct {
+		given    string
+		expected bool
+	}{
+		{given: "CREATE TABLE company (id INT)", expected: true},
+		{given: "/* comment */ create temporary table company (id int)", expected: true},
+		{given: "CREATE GLOBAL TEMPORARY TABLE company (id NUMBER)", expected: true},
+		{given: "CREATE UNLOGGED TABLE company (id INT)", expected: true},
+		{given: "CREATE TABLE IF NOT EXISTS company (id INT)", expected: false},
+		{given: "CREATE /* comment */ TABLE IF /* comment */ NOT -- comment\nEXISTS company (id INT)", expected: false},
+		{given: "CREATE TABLE /*!32312 IF NOT EXISTS*/ `company` (`id` int)", expected: false},
+		{given: "CREATE TABLE company_backup AS SELECT * FROM company", expected: true},
+		{given: "CREATE TABLE company_backup SELECT * FROM company", expected: true},
+		{given: "CREATE TABLE IF NOT EXISTS company_backup AS SELECT * FROM company", expected: false},
+		{given: "CREATE /* comment */ TABLE -- comment\ncompany_backup AS SELECT * FROM company", expected: true},
+		{given: "CREATE INDEX idx_company ON company (id)", expected: false},
+		{given: "CREATE VIEW company_table AS SELECT * FROM company", expected: false},
+		{given: "ALTER TABLE company ADD name TEXT", expected: false},
+		{given: "", expected: false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.given, func(t *testing.T) {
+			assert.Equal(t, c.expected, IsCreateTable(c.given))
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package sqlx
+
+import (
+	"context"
+	stdsql "database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	vp "vitess.io/vitess/go/vt/sqlparser"
+)
+
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*stdsql.Rows, error)
+}
+
+// IsReadOnly returns true if the given SQL looks like a read-only query.
+func IsReadOnly(sql string) bool {
+	fs := strings.Fields(vp.StripLeadingComments(sql))
+	if len(fs) == 0 {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimLeft(fs[0], "(")) {
+	case "select", "with", "values", "table",
+		"show", "describe", "desc", "explain":
+		return true
+	}
+
+	return false
+}
+
+// IsSingleSelect returns true if the given SQL is exactly one SELECT statement,
+// including the one with common table expressions,
+// but not SELECT INTO or any statement modifying data.
+func IsSingleSelect(sql string) bool {
+	var (
+		first, main string
+		ended       bool
+	)
+
+	for _, w := range words(sql) {
+		if ended && w != ";" {
+			// Stacked statements.
+			return false
+		}
+
+		switch w {
+		case ";":
+			ended = true
+			continue
+		case "into", "insert", "update", "delete", "merge", "exec", "execute", "call":
+			return false
+		}
+
+		if first == "" {
+			first = w
+			if first != "select" && first != "with" {
+				return false
+			}
+
+			continue
+		}
+
+		if main == "" && w == "select" {
+			main = w
+		}
+	}
+
+	return first == "select" || first == "with" && main == "select"
+}
+
+// words returns the lower case keywords/identifiers and semicolons of the given SQL in order,
+// the literals, quoted identifiers and comments are skipped,
+// but the executable comments of MySQL are not, like /*!32312 IF NOT EXISTS*/.
+func words(sql string) []string {
+	var (
+		ws []string
+		n  = len(sql)
+	)
+
+	for i := 0; i < n; {
+		c := sql[i]
+
+		switch {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			q := c
+			if q == '[' {
+				q = ']'
+			}
+
+			j := i + 1
+
+			for j < n {
+				if sql[j] == '\\' && q == '\'' {
+					j += 2
+					continue
+				}
+
+				if sql[j] == q {
+					if j+1 < n && sql[j+1] == q {
+						j += 2
+						continue
+					}
+
+					break
+				}
+
+				j++
+			}
+
+			i = j + 1
+		case strings.HasPrefix(sql[i:], "/*!") || strings.HasPrefix(sql[i:], "/*M!"):
+			// Skip the opening and the version, the closing is skipped as punctuations.
+			j := i + strings.IndexByte(sql[i:], '!') + 1
+			for j < n && '0' <= sql[j] && sql[j] <= '9' {
+				j++
+			}
+
+			i = j
+		case c == '-' && i+1 < n && sql[i+1] == '-':
+			j := strings.IndexByte(sql[i:], '\n')
+			if j < 0 {
+				return ws
+			}
+
+			i += j
+		case c == '/' && i+1 < n && sql[i+1] == '*':
+			j := strings.Index(sql[i+2:], "*/")
+			if j < 0 {
+				return ws
+			}
+
+			i += j + 4
+		case c == ';':
+			ws = append(ws, ";")
+			i++
+		case isIdentChar(c):
+			j := i + 1
+			for j < n && isIdentChar(sql[j]) {
+				j++
+			}
+
+			ws = append(ws, strings.ToLower(sql[i:j]))
+			i = j
+		default:
+			i++
+		}
+	}
+
+	return ws
+}
+
+// Query executes the given SQL with the queryer,
+// and returns the rows with typed values.
+func Query(ctx context.Context, qr Queryer, sql string, args ...any) (cols []string, rows [][]any, err error) {
+	rs, err := qr.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() { _ = rs.Close() }()
+
+	cts, err := rs.ColumnTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cols = make([]string, len(cts))
+	for i := range cts {
+		cols[i] = cts[i].Name()
+	}
+
+	for rs.Next() {
+		vs := make([]any, len(cts))
+
+		vps := make([]any, len(cts))
+		for i := range vs {
+			vps[i] = &vs[i]
+		}
+
+		if err = rs.Scan(vps...); err != nil {
+			return nil, nil, err
+		}
+
+		for i := range vs {
+			vs[i] = typed(cts[i], vs[i])
+		}
+
+		rows = append(rows, vs)
+	}
+
+	if err = rs.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	fields := logFields(ctx, sql, args)
+	fields["rows"] = len(rows)
+
+	tflog.Debug(ctx, "Queried", fields)
+
+	return cols, rows, nil
+}
+
+// typed converts the scanned value to the JSON friendly type
+// by the database type of the column.
+func typed(ct *stdsql.ColumnType, v any) any {
+	switch t := v.(type) {
+	case nil, bool, int64, float64:
+		return t
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	case []byte:
+		return typedString(ct, string(t))
+	case string:
+		return typedString(ct, t)
+	}
+
+	return v
+}
+
+func typedString(ct *stdsql.ColumnType, s string) any {
+	dtn := strings.ToUpper(ct.DatabaseTypeName())
+
+	switch {
+	case strings.Contains(dtn, "INT") ||
+		strings.Contains(dtn, "DECIMAL") ||
+		strings.Contains(dtn, "NUMERIC") ||
+		strings.Contains(dtn, "NUMBER") ||
+		strings.Contains(dtn, "FLOAT") ||
+		strings.Contains(dtn, "DOUBLE") ||
+		strings.Contains(dtn, "REAL") ||
+		strings.Contains(dtn, "MONEY"):
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return json.Number(s)
+		}
+	case dtn == "BOOL" || dtn == "BOOLEAN":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case dtn == "JSON" || dtn == "JSONB":
+		var j any
+		if err := json.Unmarshal([]byte(s), &j); err == nil {
+			return j
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,53 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnly(t *testing.T) {
+	tc := []struct {
+		given    string
+		expected bool
+	}{
+		{given: "SELECT id, name FROM company WHERE id = 1", expected: true},
+		{given: "/* comment */ select * from company", expected: true},
+		{given: "WITH c AS (SELECT * FROM company) SELECT COUNT(*) FROM c", expected: true},
+		{given: "(SELECT 1) UNION (SELECT 2)", expected: true},
+		{given: "SHOW TABLES", expected: true},
+		{given: "INSERT INTO company (name) VALUES ('Paul')", expected: false},
+		{given: "DELETE FROM company", expected: false},
+		{given: "ANALYZE TABLE company", expected: false},
+		{given: "", expected: false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.given, func(t *testing.T) {
+			assert.Equal(t, c.expected, IsReadOnly(c.given))
+		})
+	}
+}
+
+func TestIsSingleSelect(t *testing.T) {
+	tc := []struct {
+		given    string
+		expected bool
+	}{
+		{given: "SELECT id, name FROM company WHERE id = 1;", expected: true},
+		{given: "WITH c AS (SELECT * FROM company) SELECT COUNT(*) FROM c", expected: true},
+		{given: "(SELECT 1) UNION (SELECT 2)", expected: true},
+		{given: "SELECT 'a; DELETE FROM company', [delete] FROM company -- ; DROP TABLE company", expected: true},
+		{given: "WITH c AS (SELECT * FROM company) DELETE FROM c", expected: false},
+		{given: "SELECT 1; DELETE FROM company", expected: false},
+		{given: "SELECT * INTO backup FROM company", expected: false},
+		{given: "EXEC sp_who", expected: false},
+		{given: "", expected: false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.given, func(t *testing.T) {
+			assert.Equal(t, c.expected, IsSingleSelect(c.given))
+		})
+	}
+}
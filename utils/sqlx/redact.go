@@ -0,0 +1,166 @@
+package sqlx
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// MaskSecrets returns the context to mask the secrets of the given address in logs.
+func MaskSecrets(ctx context.Context, addr string) context.Context {
+	ss := Secrets(addr)
+	if len(ss) == 0 {
+		return ctx
+	}
+
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, ss...)
+	ctx = tflog.MaskMessageStrings(ctx, ss...)
+
+	return ctx
+}
+
+type redactLiteralsKey struct{}
+
+// WithRedactLiterals returns the context to redact the literal values of the logged SQL.
+func WithRedactLiterals(ctx context.Context) context.Context {
+	return context.WithValue(ctx, redactLiteralsKey{}, true)
+}
+
+func isRedactLiterals(ctx context.Context) bool {
+	v, _ := ctx.Value(redactLiteralsKey{}).(bool)
+	return v
+}
+
+// RedactSQL returns the given SQL to log,
+// the literal values are replaced with ? if the context requires.
+func RedactSQL(ctx context.Context, sql string) string {
+	if !isRedactLiterals(ctx) {
+		return sql
+	}
+
+	return redactLiterals(sql)
+}
+
+// logFields returns the logging fields of the given SQL and its arguments,
+// the arguments are omitted if the context requires to redact the literal values.
+func logFields(ctx context.Context, sql string, args []any) map[string]any {
+	if !isRedactLiterals(ctx) {
+		return map[string]any{"sql": sql, "args": args}
+	}
+
+	return map[string]any{"sql": redactLiterals(sql), "args": len(args)}
+}
+
+// redactLiterals replaces the string and numeric literals of the given SQL with ?,
+// the quoted identifiers and comments are kept,
+// the backslash always escapes inside the string literal, which may redact more than expected.
+func redactLiterals(sql string) string {
+	var (
+		n   = len(sql)
+		out = make([]byte, 0, n)
+	)
+
+	for i := 0; i < n; {
+		c := sql[i]
+
+		switch {
+		case c == '\'':
+			// String literal, the quote is escaped by doubling or backslash.
+			j := i + 1
+
+			for j < n {
+				if sql[j] == '\\' {
+					j += 2
+					continue
+				}
+
+				if sql[j] == '\'' {
+					if j+1 < n && sql[j+1] == '\'' {
+						j += 2
+						continue
+					}
+
+					break
+				}
+
+				j++
+			}
+
+			// Drop the prefix of typed string literal, like N'...', E'...' or X'...'.
+			if i > 0 && isLiteralPrefix(sql[i-1]) && (i == 1 || !isIdentChar(sql[i-2])) {
+				out = out[:len(out)-1]
+			}
+
+			out = append(out, '?')
+
+			i = j + 1
+		case c == '"' || c == '`' || c == '[':
+			// Quoted identifier.
+			q := c
+			if q == '[' {
+				q = ']'
+			}
+
+			j := strings.IndexByte(sql[i+1:], q)
+			if j < 0 {
+				return string(append(out, sql[i:]...))
+			}
+
+			out = append(out, sql[i:i+j+2]...)
+			i += j + 2
+		case c == '-' && i+1 < n && sql[i+1] == '-':
+			// Line comment.
+			j := strings.IndexByte(sql[i:], '\n')
+			if j < 0 {
+				return string(append(out, sql[i:]...))
+			}
+
+			out = append(out, sql[i:i+j]...)
+			i += j
+		case c == '/' && i+1 < n && sql[i+1] == '*':
+			// Block comment.
+			j := strings.Index(sql[i+2:], "*/")
+			if j < 0 {
+				return string(append(out, sql[i:]...))
+			}
+
+			out = append(out, sql[i:i+j+4]...)
+			i += j + 4
+		case isDigit(c) && (i == 0 || !isIdentChar(sql[i-1]) && sql[i-1] != ':'):
+			// Numeric literal, including the decimal and exponent, but not the placeholder like :1.
+			j := i + 1
+			for j < n && (isIdentChar(sql[j]) || sql[j] == '.' ||
+				((sql[j] == '+' || sql[j] == '-') && (sql[j-1] == 'e' || sql[j-1] == 'E'))) {
+				j++
+			}
+
+			out = append(out, '?')
+
+			i = j
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+
+	return string(out)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c == '@' || c == '#' || isDigit(c) ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c >= 0x80
+}
+
+func isLiteralPrefix(c byte) bool {
+	switch c {
+	case 'N', 'n', 'E', 'e', 'X', 'x', 'B', 'b':
+		return true
+	}
+
+	return false
+}
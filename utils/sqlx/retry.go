@@ -0,0 +1,181 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/lib/pq"
+	"github.com/sijms/go-ora/v2/network"
+)
+
+// IsTransientError returns true if the given error is caused by
+// deadlock, serialization failure or dropped connection,
+// which is worth retrying.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+
+	// MySQL.
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		switch myErr.Number {
+		case 1205, // Lock wait timeout exceeded.
+			1213: // Deadlock found.
+			return true
+		}
+
+		return false
+	}
+
+	// Postgres.
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", // Serialization failure.
+			"40P01", // Deadlock detected.
+			"57P01": // Admin shutdown.
+			return true
+		}
+
+		return false
+	}
+
+	// SQLServer.
+	var msErr mssql.Error
+	if errors.As(err, &msErr) {
+		// Chosen as deadlock victim.
+		return msErr.Number == 1205
+	}
+
+	// Oracle.
+	var oraErr *network.OracleError
+	if errors.As(err, &oraErr) {
+		// Deadlock detected.
+		return oraErr.ErrCode == 60
+	}
+
+	return false
+}
+
+// isOutcomeUnknown returns true if the given error is caused by
+// dropping the connection after the request may have been performed,
+// e.g. the connection is dropped while committing.
+func isOutcomeUnknown(err error) bool {
+	if errors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// Admin shutdown.
+		return pqErr.Code == "57P01"
+	}
+
+	return false
+}
+
+// Retry retries the transient errors with exponential backoff.
+type Retry struct {
+	// Attempts specifies the maximum retrying times,
+	// disables retrying if not positive.
+	Attempts int
+	// Backoff specifies the waiting duration before the first retrying,
+	// which is doubled at each retrying.
+	Backoff time.Duration
+	// BackoffMax specifies the maximum waiting duration,
+	// unlimited if not positive.
+	BackoffMax time.Duration
+	// Retried counts the retrying times if not nil.
+	Retried *atomic.Int64
+}
+
+// Do calls the given function,
+// and calls again if the returning error is transient,
+// but not if the outcome of the calling is unknown,
+// as the given function may not be idempotent, like inserting rows.
+func (r Retry) Do(ctx context.Context, fn func(context.Context) error) error {
+	return r.do(ctx, fn, false)
+}
+
+// DoIdempotent is similar to Do,
+// but also calls again if the outcome of the calling is unknown,
+// the given function must be idempotent, like setting session variables.
+func (r Retry) DoIdempotent(ctx context.Context, fn func(context.Context) error) error {
+	return r.do(ctx, fn, true)
+}
+
+func (r Retry) do(ctx context.Context, fn func(context.Context) error, idempotent bool) error {
+	backoff := r.Backoff
+
+	for i := 0; ; i++ {
+		err := fn(ctx)
+		if err == nil || i >= r.Attempts || !IsTransientError(err) {
+			return err
+		}
+
+		if !idempotent && isOutcomeUnknown(err) {
+			tflog.Warn(ctx, "Cannot retry with unknown outcome", map[string]any{
+				"error": err.Error(),
+			})
+
+			return err
+		}
+
+		if r.Retried != nil {
+			r.Retried.Add(1)
+		}
+
+		tflog.Warn(ctx, "Retrying transient error", map[string]any{
+			"error":   err.Error(),
+			"attempt": i + 1,
+			"backoff": backoff.String(),
+		})
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+
+		backoff *= 2
+		if r.BackoffMax > 0 && backoff > r.BackoffMax {
+			backoff = r.BackoffMax
+		}
+	}
+}
+
+// Exec is similar to the package Exec,
+// but retries the transient errors as Do,
+// the given executor should be a database pool,
+// so that each retrying takes a healthy connection.
+func (r Retry) Exec(ctx context.Context, ex Executor, sql string, args ...any) error {
+	return r.Do(ctx, func(ctx context.Context) error {
+		return Exec(ctx, ex, sql, args...)
+	})
+}
+
+// ExecAffected is similar to the package ExecAffected,
+// but retries the transient errors as Exec.
+func (r Retry) ExecAffected(ctx context.Context, ex Executor, sql string, args ...any) (int64, error) {
+	var n int64
+
+	err := r.Do(ctx, func(ctx context.Context) (err error) {
+		n, err = ExecAffected(ctx, ex, sql, args...)
+		return err
+	})
+
+	return n, err
+}
@@ -0,0 +1,170 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/sijms/go-ora/v2/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tc := []struct {
+		given    error
+		expected bool
+	}{
+		{
+			given:    nil,
+			expected: false,
+		},
+		{
+			given:    errors.New("deadlock"),
+			expected: false,
+		},
+		{
+			given:    driver.ErrBadConn,
+			expected: true,
+		},
+		{
+			given:    fmt.Errorf("wrapped: %w", &mysql.MySQLError{Number: 1213}),
+			expected: true,
+		},
+		{
+			given:    &mysql.MySQLError{Number: 1205},
+			expected: true,
+		},
+		{
+			given:    &mysql.MySQLError{Number: 1062},
+			expected: false,
+		},
+		{
+			given:    &pq.Error{Code: "40001"},
+			expected: true,
+		},
+		{
+			given:    &pq.Error{Code: "40P01"},
+			expected: true,
+		},
+		{
+			given:    &pq.Error{Code: "57P01"},
+			expected: true,
+		},
+		{
+			given:    &pq.Error{Code: "23505"},
+			expected: false,
+		},
+		{
+			given:    mssql.Error{Number: 1205},
+			expected: true,
+		},
+		{
+			given:    mssql.Error{Number: 2627},
+			expected: false,
+		},
+		{
+			given:    &network.OracleError{ErrCode: 60},
+			expected: true,
+		},
+		{
+			given:    &network.OracleError{ErrCode: 1},
+			expected: false,
+		},
+	}
+
+	for i, c := range tc {
+		t.Run("case "+strconv.Itoa(i), func(t *testing.T) {
+			actual := IsTransientError(c.given)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestRetry_Do(t *testing.T) {
+	transient := &mysql.MySQLError{Number: 1213}
+	dropped := fmt.Errorf("cannot commit: %w", mysql.ErrInvalidConn)
+
+	tc := []struct {
+		given         Retry
+		idempotent    bool
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			given:         Retry{Attempts: 3, Backoff: time.Millisecond},
+			errs:          []error{transient, transient, nil},
+			expectedCalls: 3,
+			expectedErr:   nil,
+		},
+		{
+			given:         Retry{Attempts: 1, Backoff: time.Millisecond},
+			errs:          []error{transient, transient, nil},
+			expectedCalls: 2,
+			expectedErr:   transient,
+		},
+		{
+			given:         Retry{},
+			errs:          []error{transient, nil},
+			expectedCalls: 1,
+			expectedErr:   transient,
+		},
+		{
+			given:         Retry{Attempts: 3, Backoff: time.Millisecond},
+			errs:          []error{driver.ErrSkip, nil},
+			expectedCalls: 1,
+			expectedErr:   driver.ErrSkip,
+		},
+		{
+			given:         Retry{Attempts: 3, Backoff: time.Millisecond},
+			errs:          []error{driver.ErrBadConn, nil},
+			expectedCalls: 2,
+			expectedErr:   nil,
+		},
+		{
+			given:         Retry{Attempts: 3, Backoff: time.Millisecond},
+			errs:          []error{dropped, nil},
+			expectedCalls: 1,
+			expectedErr:   dropped,
+		},
+		{
+			given:         Retry{Attempts: 3, Backoff: time.Millisecond},
+			idempotent:    true,
+			errs:          []error{dropped, nil},
+			expectedCalls: 2,
+			expectedErr:   nil,
+		},
+	}
+
+	for i, c := range tc {
+		t.Run("case "+strconv.Itoa(i), func(t *testing.T) {
+			var (
+				calls   int
+				retried atomic.Int64
+			)
+
+			c.given.Retried = &retried
+
+			do := c.given.Do
+			if c.idempotent {
+				do = c.given.DoIdempotent
+			}
+
+			err := do(context.Background(), func(ctx context.Context) error {
+				calls++
+				return c.errs[calls-1]
+			})
+			assert.Equal(t, c.expectedCalls, calls)
+			assert.Equal(t, c.expectedErr, err)
+			assert.Equal(t, int64(c.expectedCalls-1), retried.Load())
+		})
+	}
+}
@@ -0,0 +1,433 @@
+package sqlx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/seal-io/terraform-provider-byteset/utils/strx"
+)
+
+const (
+	TLSModeDisable    = "disable"
+	TLSModeRequire    = "require"
+	TLSModeVerifyCA   = "verify-ca"
+	TLSModeVerifyFull = "verify-full"
+)
+
+// TLS specifies the TLS configuration of the database connections.
+type TLS struct {
+	// Mode specifies how to secure the connections,
+	// disable, require, verify-ca or verify-full.
+	Mode string
+	// CA specifies the PEM encoded certificate authorities to verify the server,
+	// uses the system certificate authorities if blank.
+	CA string
+	// Cert specifies the PEM encoded client certificate.
+	Cert string
+	// Key specifies the PEM encoded private key of the client certificate.
+	Key string
+	// ServerName specifies the host name to verify the server certificate with,
+	// uses the host of address if blank.
+	ServerName string
+}
+
+// WithTLS returns the given database address applied the given TLS configuration,
+// via the TLS mechanism of each driver.
+func WithTLS(addr string, t TLS) (string, error) {
+	switch t.Mode {
+	case TLSModeDisable, TLSModeRequire, TLSModeVerifyCA, TLSModeVerifyFull:
+	default:
+		return "", fmt.Errorf("unknown TLS mode %q", t.Mode)
+	}
+
+	if (t.Cert == "") != (t.Key == "") {
+		return "", errors.New("TLS client certificate and key must be specified together")
+	}
+
+	drv, dsn, err := ParseAddress(addr)
+	if err != nil {
+		return "", err
+	}
+
+	switch drv {
+	case MySQLDialect:
+		return withTLSMySQL(addr, dsn, t)
+	case PostgresDialect:
+		return withTLSPostgres(addr, t)
+	case SQLServerDialect:
+		return withTLSSQLServer(addr, t)
+	case OracleDialect:
+		return withTLSOracle(addr, t)
+	}
+
+	return "", fmt.Errorf("cannot configure TLS on %s", drv)
+}
+
+// withTLSMySQL registers the TLS configuration to the MySQL driver,
+// and refers it via the tls parameter.
+func withTLSMySQL(addr, dsn string, t TLS) (string, error) {
+	v := "false"
+
+	if t.Mode != TLSModeDisable {
+		cfg, err := tlsConfig(t)
+		if err != nil {
+			return "", err
+		}
+
+		// Register with the digest of the configuration,
+		// so that the same configuration is registered once.
+		v = "byteset-" + strx.Sum(t.Mode, t.CA, t.Cert, t.Key, t.ServerName)
+
+		err = mysql.RegisterTLSConfig(v, cfg)
+		if err != nil {
+			return "", fmt.Errorf("cannot register TLS config: %w", err)
+		}
+	}
+
+	// Format: [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...],
+	// the password may contain ?, so splits the parameters after the last /.
+	scheme := addr[:len(addr)-len(dsn)]
+
+	path, rawQuery := dsn, ""
+	if i := strings.LastIndex(dsn, "/"); i >= 0 {
+		if j := strings.Index(dsn[i:], "?"); j >= 0 {
+			path, rawQuery = dsn[:i+j], dsn[i+j+1:]
+		}
+	}
+
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse database address: %w", RedactError(err, addr))
+	}
+
+	q.Set("tls", v)
+
+	return scheme + path + "?" + q.Encode(), nil
+}
+
+// tlsConfig returns the tls.Config of the given TLS configuration.
+func tlsConfig(t TLS) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName: t.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if t.CA != "" {
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM([]byte(t.CA)) {
+			return nil, errors.New("cannot parse TLS CA")
+		}
+	}
+
+	if t.Cert != "" {
+		cert, err := tls.X509KeyPair([]byte(t.Cert), []byte(t.Key))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse TLS client certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	switch t.Mode {
+	case TLSModeRequire:
+		cfg.InsecureSkipVerify = true
+	case TLSModeVerifyCA:
+		// Verify the certificate chain without the host name.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyPeerCertificate = func(raws [][]byte, _ [][]*x509.Certificate) error {
+			return verifyChain(cfg.RootCAs, raws)
+		}
+	}
+
+	return cfg, nil
+}
+
+func verifyChain(roots *x509.CertPool, raws [][]byte) error {
+	if len(raws) == 0 {
+		return errors.New("no server certificate")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(raws))
+
+	for i := range raws {
+		c, err := x509.ParseCertificate(raws[i])
+		if err != nil {
+			return err
+		}
+
+		certs = append(certs, c)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, c := range certs[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+
+	_, err := certs[0].Verify(opts)
+
+	return err
+}
+
+// withTLSPostgres writes the PEM contents into private temporary files,
+// and passes their paths to lib/pq, which requires the private key file is not accessible by others.
+func withTLSPostgres(addr string, t TLS) (string, error) {
+	if t.ServerName != "" {
+		return "", errors.New("cannot specify TLS server name on postgres")
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse database address: %w", RedactError(err, addr))
+	}
+
+	q := u.Query()
+	q.Set("sslmode", t.Mode)
+
+	for _, k := range []string{"sslinline", "sslrootcert", "sslcert", "sslkey"} {
+		q.Del(k)
+	}
+
+	if t.Mode != TLSModeDisable {
+		for _, f := range []struct {
+			param, kind, contents string
+		}{
+			{param: "sslrootcert", kind: "ca", contents: t.CA},
+			{param: "sslcert", kind: "cert", contents: t.Cert},
+			{param: "sslkey", kind: "key", contents: t.Key},
+		} {
+			if f.contents == "" {
+				continue
+			}
+
+			p, err := writePEM(f.kind, f.contents)
+			if err != nil {
+				return "", err
+			}
+
+			q.Set(f.param, p)
+		}
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// withTLSSQLServer configures the encrypt parameters of go-mssqldb,
+// the CA is written into a private temporary file as the certificate parameter requires,
+// the host name is always verified if verifying.
+func withTLSSQLServer(addr string, t TLS) (string, error) {
+	if t.Cert != "" {
+		return "", errors.New("cannot specify TLS client certificate on mssql")
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse database address: %w", RedactError(err, addr))
+	}
+
+	q := u.Query()
+
+	for _, k := range []string{"encrypt", "TrustServerCertificate", "certificate", "hostNameInCertificate"} {
+		q.Del(k)
+	}
+
+	switch t.Mode {
+	case TLSModeDisable:
+		q.Set("encrypt", "disable")
+	case TLSModeRequire:
+		q.Set("encrypt", "true")
+		q.Set("TrustServerCertificate", "true")
+	default:
+		q.Set("encrypt", "true")
+		q.Set("TrustServerCertificate", "false")
+
+		if t.CA != "" {
+			p, err := writePEM("ca", t.CA)
+			if err != nil {
+				return "", err
+			}
+
+			q.Set("certificate", p)
+		}
+
+		if t.ServerName != "" {
+			q.Set("hostNameInCertificate", t.ServerName)
+		}
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// pemFiles caches the temporary files created by writePEM by the digest of the contents,
+// and counts the references of each file.
+var pemFiles = struct {
+	sync.Mutex
+	paths map[string]string
+	refs  map[string]int
+}{
+	paths: map[string]string{},
+	refs:  map[string]int{},
+}
+
+// writePEM writes the given PEM contents of the given kind into a temporary file,
+// which is created exclusively and readable by the current user only,
+// and returns the path of the file,
+// the file is reused within the process for the same contents,
+// and removed once all references are released by releasePEM.
+func writePEM(kind, contents string) (string, error) {
+	dg := strx.Sum(kind, contents)
+
+	pemFiles.Lock()
+	defer pemFiles.Unlock()
+
+	if p, ok := pemFiles.paths[dg]; ok {
+		if bs, err := os.ReadFile(p); err == nil && string(bs) == contents {
+			pemFiles.refs[p]++
+			return p, nil
+		}
+	}
+
+	f, err := os.CreateTemp("", "byteset-"+kind+"-*.pem")
+	if err != nil {
+		return "", fmt.Errorf("cannot write TLS %s: %w", kind, err)
+	}
+
+	err = f.Chmod(0o600)
+	if err == nil {
+		_, err = f.WriteString(contents)
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("cannot write TLS %s: %w", kind, err)
+	}
+
+	pemFiles.paths[dg] = f.Name()
+	pemFiles.refs[f.Name()] = 1
+
+	return f.Name(), nil
+}
+
+// releasePEM releases a reference of each given file created by writePEM,
+// and removes the file without any reference.
+func releasePEM(paths ...string) {
+	pemFiles.Lock()
+	defer pemFiles.Unlock()
+
+	for _, p := range paths {
+		n, ok := pemFiles.refs[p]
+		if !ok {
+			continue
+		}
+
+		if n > 1 {
+			pemFiles.refs[p] = n - 1
+			continue
+		}
+
+		delete(pemFiles.refs, p)
+
+		for dg := range pemFiles.paths {
+			if pemFiles.paths[dg] == p {
+				delete(pemFiles.paths, dg)
+			}
+		}
+
+		_ = os.Remove(p)
+	}
+}
+
+// pemPathsOf returns the files created by writePEM which are referred by the given database address.
+func pemPathsOf(addr string) []string {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil
+	}
+
+	q := u.Query()
+
+	pemFiles.Lock()
+	defer pemFiles.Unlock()
+
+	var ps []string
+
+	for _, k := range []string{"sslrootcert", "sslcert", "sslkey", "certificate"} {
+		p := q.Get(k)
+		if _, ok := pemFiles.refs[p]; ok && p != "" {
+			ps = append(ps, p)
+		}
+	}
+
+	return ps
+}
+
+// pemConnector releases the files created by writePEM at closing.
+type pemConnector struct {
+	driver.Connector
+
+	paths []string
+}
+
+func (c pemConnector) Close() error {
+	defer releasePEM(c.paths...)
+
+	if cl, ok := c.Connector.(io.Closer); ok {
+		return cl.Close()
+	}
+
+	return nil
+}
+
+// withTLSOracle configures the SSL options of go-ora,
+// the certificates must be provided by the WALLET option of address,
+// the host name is always verified if verifying.
+func withTLSOracle(addr string, t TLS) (string, error) {
+	if t.CA != "" || t.Cert != "" || t.ServerName != "" {
+		return "", errors.New("cannot specify TLS certificates or server name on oracle, " +
+			"use the WALLET option of address instead")
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse database address: %w", RedactError(err, addr))
+	}
+
+	q := u.Query()
+	q.Del("SSL")
+	q.Del("SSL VERIFY")
+
+	switch t.Mode {
+	case TLSModeDisable:
+		q.Set("SSL", "false")
+	case TLSModeRequire:
+		q.Set("SSL", "true")
+		q.Set("SSL VERIFY", "false")
+	default:
+		q.Set("SSL", "true")
+		q.Set("SSL VERIFY", "true")
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
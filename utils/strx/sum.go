@@ -3,6 +3,7 @@ package strx
 import (
 	"encoding/hex"
 	"hash/fnv"
+	"io"
 )
 
 func Sum(ss ...string) string {
@@ -17,3 +18,16 @@ func Sum(ss ...string) string {
 
 	return hex.EncodeToString(h.Sum(nil))
 }
+
+// SumReader is similar to Sum,
+// but consumes the given reader.
+func SumReader(r io.Reader) (string, error) {
+	h := fnv.New64a()
+
+	_, err := io.Copy(h, r)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}